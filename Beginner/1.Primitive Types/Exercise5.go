@@ -13,6 +13,6 @@ func main() {
 	fmt.Print("enter the temperature in celcius")
 	fmt.Scanln(&tempC)
 
-	fmt.Println("The temperature in fahrenheit is ", (tempC*(9/5))+32)
+	fmt.Println("The temperature in fahrenheit is ", tempC*9/5+32)
 
 }
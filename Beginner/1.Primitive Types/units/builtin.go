@@ -0,0 +1,76 @@
+package units
+
+// Sizes of some units in base units, shared by the registry and the
+// typed quantities.
+const (
+	inch = 0.0254
+	foot = 12 * inch
+	yard = 3 * foot
+	mile = 1760 * yard
+
+	pound = 0.45359237
+	ounce = pound / 16
+	stone = 14 * pound
+
+	minute = 60
+	hour   = 60 * minute
+	day    = 24 * hour
+	week   = 7 * day
+	year   = 365.25 * day // Julian year
+
+	kilobyte = 1000
+	kibibyte = 1024
+)
+
+func newDefault() *Registry {
+	r := NewRegistry()
+	r.mustRegister(
+		// "37.5c" and "98f" are common enough to accept, so the lower case
+		// letters are names; no other unit has them as a symbol.
+		Unit{Symbol: "K", Names: []string{"kelvin"}, Dimension: DimTemperature, Scale: 1},
+		Unit{Symbol: "C", Names: []string{"c", "°C", "celsius", "centigrade"}, Dimension: DimTemperature, Scale: 1, Offset: 273.15},
+		Unit{Symbol: "F", Names: []string{"f", "°F", "fahrenheit"}, Dimension: DimTemperature, Scale: 5.0 / 9, Offset: 459.67},
+		Unit{Symbol: "R", Names: []string{"°R", "rankine"}, Dimension: DimTemperature, Scale: 5.0 / 9},
+
+		Unit{Symbol: "mm", Names: []string{"millimetre", "millimetres", "millimeter", "millimeters"}, Dimension: DimLength, Scale: 0.001},
+		Unit{Symbol: "cm", Names: []string{"centimetre", "centimetres", "centimeter", "centimeters"}, Dimension: DimLength, Scale: 0.01},
+		Unit{Symbol: "m", Names: []string{"metre", "metres", "meter", "meters"}, Dimension: DimLength, Scale: 1},
+		Unit{Symbol: "km", Names: []string{"kilometre", "kilometres", "kilometer", "kilometers"}, Dimension: DimLength, Scale: 1000},
+		Unit{Symbol: "in", Names: []string{"inch", "inches", "\""}, Dimension: DimLength, Scale: inch},
+		Unit{Symbol: "ft", Names: []string{"foot", "feet", "'"}, Dimension: DimLength, Scale: foot},
+		Unit{Symbol: "yd", Names: []string{"yard", "yards"}, Dimension: DimLength, Scale: yard},
+		Unit{Symbol: "mi", Names: []string{"mile", "miles"}, Dimension: DimLength, Scale: mile},
+		Unit{Symbol: "nmi", Names: []string{"nautical mile", "nautical miles"}, Dimension: DimLength, Scale: 1852},
+
+		Unit{Symbol: "mg", Names: []string{"milligram", "milligrams"}, Dimension: DimMass, Scale: 1e-6},
+		Unit{Symbol: "g", Names: []string{"gram", "grams"}, Dimension: DimMass, Scale: 0.001},
+		Unit{Symbol: "kg", Names: []string{"kilogram", "kilograms", "kilo", "kilos"}, Dimension: DimMass, Scale: 1},
+		Unit{Symbol: "t", Names: []string{"tonne", "tonnes"}, Dimension: DimMass, Scale: 1000},
+		Unit{Symbol: "oz", Names: []string{"ounce", "ounces"}, Dimension: DimMass, Scale: ounce},
+		Unit{Symbol: "lb", Names: []string{"lbs", "pound", "pounds"}, Dimension: DimMass, Scale: pound},
+		Unit{Symbol: "st", Names: []string{"stone", "stones"}, Dimension: DimMass, Scale: stone},
+
+		Unit{Symbol: "ms", Names: []string{"millisecond", "milliseconds"}, Dimension: DimTime, Scale: 0.001},
+		Unit{Symbol: "s", Names: []string{"sec", "second", "seconds"}, Dimension: DimTime, Scale: 1},
+		Unit{Symbol: "min", Names: []string{"minute", "minutes"}, Dimension: DimTime, Scale: minute},
+		Unit{Symbol: "h", Names: []string{"hr", "hour", "hours"}, Dimension: DimTime, Scale: hour},
+		Unit{Symbol: "d", Names: []string{"day", "days"}, Dimension: DimTime, Scale: day},
+		Unit{Symbol: "wk", Names: []string{"week", "weeks"}, Dimension: DimTime, Scale: week},
+		Unit{Symbol: "yr", Names: []string{"year", "years"}, Dimension: DimTime, Scale: year},
+		// A dog ages seven years for each human one, the rule of thumb in
+		// Exercise7.go, so a dog year is a seventh of a year.
+		Unit{Symbol: "dogyr", Names: []string{"dog year", "dog years"}, Dimension: DimTime, Scale: year / 7},
+
+		Unit{Symbol: "bit", Names: []string{"bits"}, Dimension: DimDataSize, Scale: 0.125},
+		Unit{Symbol: "B", Names: []string{"byte", "bytes"}, Dimension: DimDataSize, Scale: 1},
+		Unit{Symbol: "kB", Names: []string{"kilobyte", "kilobytes"}, Dimension: DimDataSize, Scale: kilobyte},
+		Unit{Symbol: "MB", Names: []string{"megabyte", "megabytes"}, Dimension: DimDataSize, Scale: kilobyte * kilobyte},
+		Unit{Symbol: "GB", Names: []string{"gigabyte", "gigabytes"}, Dimension: DimDataSize, Scale: kilobyte * kilobyte * kilobyte},
+		Unit{Symbol: "TB", Names: []string{"terabyte", "terabytes"}, Dimension: DimDataSize, Scale: kilobyte * kilobyte * kilobyte * kilobyte},
+		Unit{Symbol: "KiB", Names: []string{"kibibyte", "kibibytes"}, Dimension: DimDataSize, Scale: kibibyte},
+		Unit{Symbol: "MiB", Names: []string{"mebibyte", "mebibytes"}, Dimension: DimDataSize, Scale: kibibyte * kibibyte},
+		Unit{Symbol: "GiB", Names: []string{"gibibyte", "gibibytes"}, Dimension: DimDataSize, Scale: kibibyte * kibibyte * kibibyte},
+		Unit{Symbol: "TiB", Names: []string{"tebibyte", "tebibytes"}, Dimension: DimDataSize, Scale: kibibyte * kibibyte * kibibyte * kibibyte},
+	)
+	return r
+}
@@ -0,0 +1,101 @@
+// Command convert converts quantities between units.
+//
+//	convert 37.5C F
+//	convert 10 km to mi
+//	convert 3 dog years yr
+//	convert 1GiB          # in every unit of data size
+//	convert -list
+//
+// The last argument is the unit to convert to. If there is only a
+// quantity, it is shown in every unit of its dimension. Put -- before a
+// negative quantity so it is not taken for a flag: convert -- -40F C.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/learning-go-book/units"
+)
+
+func main() {
+	prec := flag.Int("p", 10, "significant `digits` in results, -1 for all")
+	list := flag.Bool("list", false, "list the known units")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: convert [-p digits] quantity [to] [unit]\n       convert -list")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *list {
+		listUnits()
+		return
+	}
+	if err := run(flag.Args(), *prec); err != nil {
+		fmt.Fprintln(os.Stderr, "convert:", err)
+		os.Exit(1)
+	}
+}
+
+// run converts the quantity given by args.
+func run(args []string, prec int) error {
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Try the whole of args as the quantity first, so that "3 dog years"
+	// is not read as 3 dog converted to years.
+	if q, err := units.Parse(strings.Join(args, " ")); err == nil {
+		for _, u := range units.Default.Units(q.Unit.Dimension) {
+			r, err := q.In(u)
+			if err != nil {
+				return err
+			}
+			fmt.Println(r.Format(prec))
+		}
+		return nil
+	}
+	if len(args) < 2 {
+		_, err := units.Parse(args[0])
+		return err
+	}
+
+	target := args[len(args)-1]
+	args = args[:len(args)-1]
+	if n := len(args); n > 1 && (args[n-1] == "to" || args[n-1] == "in") {
+		args = args[:n-1]
+	}
+	q, err := units.Parse(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	to, err := units.Lookup(target)
+	if err != nil {
+		return err
+	}
+	r, err := q.In(to)
+	var mismatch *units.DimensionMismatchError
+	if errors.As(err, &mismatch) {
+		return fmt.Errorf("cannot convert %v to %v", mismatch.From.Dimension, mismatch.To.Dimension)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(r.Format(prec))
+	return nil
+}
+
+func listUnits() {
+	var dim units.Dimension
+	for _, u := range units.Default.Units(0) {
+		if u.Dimension != dim {
+			dim = u.Dimension
+			fmt.Printf("%s:\n", dim)
+		}
+		fmt.Printf("  %-6s %s\n", u.Symbol, strings.Join(u.Names, ", "))
+	}
+}
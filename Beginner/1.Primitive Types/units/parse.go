@@ -0,0 +1,72 @@
+package units
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse reads a quantity written as a number followed by a unit, with or
+// without a space between them: "37.5C", "10 km", "-40 °F", "1.5e3 m",
+// "3 dog years".
+func (r *Registry) Parse(s string) (Quantity, error) {
+	s = strings.TrimSpace(s)
+	n := numberPrefix(s)
+	if n == 0 {
+		return Quantity{}, fmt.Errorf("units: %q does not start with a number", s)
+	}
+	v, err := strconv.ParseFloat(s[:n], 64)
+	if err != nil {
+		return Quantity{}, fmt.Errorf("units: bad number in %q", s)
+	}
+	name := strings.TrimSpace(s[n:])
+	if name == "" {
+		return Quantity{}, fmt.Errorf("units: %q has no unit", s)
+	}
+	u, err := r.Lookup(name)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Value: v, Unit: u}, nil
+}
+
+// Parse reads a quantity using the units in Default.
+func Parse(s string) (Quantity, error) { return Default.Parse(s) }
+
+// numberPrefix returns the length of the decimal number at the start of
+// s: an optional sign, digits with an optional fraction, and an optional
+// exponent. An "e" not followed by digits is left for the unit.
+func numberPrefix(s string) int {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	digits := 0
+	for ; i < len(s) && isDigit(s[i]); i++ {
+		digits++
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		for ; i < len(s) && isDigit(s[i]); i++ {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isDigit(s[j]) {
+			for j < len(s) && isDigit(s[j]) {
+				j++
+			}
+			i = j
+		}
+	}
+	return i
+}
+
+func isDigit(b byte) bool { return b >= '0' && b <= '9' }
@@ -0,0 +1,118 @@
+package units
+
+import "strconv"
+
+// The typed quantities store a value in the base unit of their dimension.
+// Like time.Duration, the linear ones have constants for their units, so
+// that 5*Kilometre is a Length and l.In(Mile) is a number of miles.
+
+// Length is a length in metres.
+type Length float64
+
+const (
+	Millimetre Length = 0.001
+	Centimetre Length = 0.01
+	Metre      Length = 1
+	Kilometre  Length = 1000
+	Inch       Length = inch
+	Foot       Length = foot
+	Yard       Length = yard
+	Mile       Length = mile
+)
+
+// In returns l as a number of unit.
+func (l Length) In(unit Length) float64 { return float64(l / unit) }
+
+func (l Length) String() string { return formatBase(float64(l), "m") }
+
+// Mass is a mass in kilograms.
+type Mass float64
+
+const (
+	Milligram Mass = 1e-6
+	Gram      Mass = 0.001
+	Kilogram  Mass = 1
+	Tonne     Mass = 1000
+	Ounce     Mass = ounce
+	Pound     Mass = pound
+	Stone     Mass = stone
+)
+
+// In returns m as a number of unit.
+func (m Mass) In(unit Mass) float64 { return float64(m / unit) }
+
+func (m Mass) String() string { return formatBase(float64(m), "kg") }
+
+// Duration is a length of time in seconds. Unlike time.Duration it can
+// hold spans of many years, which is what unit conversions are usually
+// about.
+type Duration float64
+
+const (
+	Millisecond Duration = 0.001
+	Second      Duration = 1
+	Minute      Duration = minute
+	Hour        Duration = hour
+	Day         Duration = day
+	Week        Duration = week
+	Year        Duration = year
+	DogYear     Duration = year / 7
+)
+
+// In returns d as a number of unit.
+func (d Duration) In(unit Duration) float64 { return float64(d / unit) }
+
+func (d Duration) String() string { return formatBase(float64(d), "s") }
+
+// DataSize is an amount of data in bytes.
+type DataSize float64
+
+const (
+	Bit      DataSize = 0.125
+	Byte     DataSize = 1
+	Kilobyte DataSize = kilobyte
+	Megabyte DataSize = 1000 * Kilobyte
+	Gigabyte DataSize = 1000 * Megabyte
+	Terabyte DataSize = 1000 * Gigabyte
+	Kibibyte DataSize = kibibyte
+	Mebibyte DataSize = 1024 * Kibibyte
+	Gibibyte DataSize = 1024 * Mebibyte
+	Tebibyte DataSize = 1024 * Gibibyte
+)
+
+// In returns s as a number of unit.
+func (s DataSize) In(unit DataSize) float64 { return float64(s / unit) }
+
+func (s DataSize) String() string { return formatBase(float64(s), "B") }
+
+// Temperature is a temperature in kelvin. Temperature scales do not share
+// a zero, so there are functions and methods for each scale rather than
+// unit constants.
+type Temperature float64
+
+// AbsoluteZero is the lowest possible temperature.
+const AbsoluteZero Temperature = 0
+
+// Kelvin returns the Temperature k kelvin.
+func Kelvin(k float64) Temperature { return Temperature(k) }
+
+// Celsius returns the Temperature c degrees Celsius.
+func Celsius(c float64) Temperature { return Temperature(c + 273.15) }
+
+// Fahrenheit returns the Temperature f degrees Fahrenheit.
+func Fahrenheit(f float64) Temperature { return Temperature((f + 459.67) * 5 / 9) }
+
+// Kelvin returns t in kelvin.
+func (t Temperature) Kelvin() float64 { return float64(t) }
+
+// Celsius returns t in degrees Celsius.
+func (t Temperature) Celsius() float64 { return float64(t) - 273.15 }
+
+// Fahrenheit returns t in degrees Fahrenheit.
+func (t Temperature) Fahrenheit() float64 { return float64(t)*9/5 - 459.67 }
+
+func (t Temperature) String() string { return formatBase(float64(t), "K") }
+
+func formatBase(v float64, symbol string) string {
+	return strconv.FormatFloat(v, 'g', -1, 64) + " " + symbol
+}
@@ -0,0 +1,169 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		l    Length
+		unit Length
+		want float64
+	}{
+		{5 * Kilometre, Metre, 5000},
+		{10 * Kilometre, Mile, 6.21371192237334},
+		{Mile, Kilometre, 1.609344},
+		{Foot, Inch, 12},
+		{Yard, Foot, 3},
+		{Metre, Centimetre, 100},
+		{Centimetre, Millimetre, 10},
+		{Inch, Centimetre, 2.54},
+	}
+	for _, tt := range tests {
+		if got := tt.l.In(tt.unit); !nearlyEqual(got, tt.want) {
+			t.Errorf("%v.In(%v) = %v, want %v", tt.l, tt.unit, got, tt.want)
+		}
+	}
+	if got := (1500 * Metre).String(); got != "1500 m" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestMass(t *testing.T) {
+	tests := []struct {
+		m    Mass
+		unit Mass
+		want float64
+	}{
+		{Tonne, Kilogram, 1000},
+		{Kilogram, Gram, 1000},
+		{Gram, Milligram, 1000},
+		{Pound, Kilogram, 0.45359237},
+		{Pound, Ounce, 16},
+		{Stone, Pound, 14},
+		{Kilogram, Pound, 2.2046226218487757},
+	}
+	for _, tt := range tests {
+		if got := tt.m.In(tt.unit); !nearlyEqual(got, tt.want) {
+			t.Errorf("%v.In(%v) = %v, want %v", tt.m, tt.unit, got, tt.want)
+		}
+	}
+	if got := (2 * Kilogram).String(); got != "2 kg" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d    Duration
+		unit Duration
+		want float64
+	}{
+		{Minute, Second, 60},
+		{Hour, Minute, 60},
+		{Day, Hour, 24},
+		{Week, Day, 7},
+		{Year, Day, 365.25},
+		{Year, DogYear, 7},
+		{3 * DogYear, Week, 3 * 365.25 / 7 / 7},
+		{Second, Millisecond, 1000},
+	}
+	for _, tt := range tests {
+		if got := tt.d.In(tt.unit); !nearlyEqual(got, tt.want) {
+			t.Errorf("%v.In(%v) = %v, want %v", tt.d, tt.unit, got, tt.want)
+		}
+	}
+	if got := (90 * Minute).String(); got != "5400 s" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestDataSize(t *testing.T) {
+	tests := []struct {
+		s    DataSize
+		unit DataSize
+		want float64
+	}{
+		{Gibibyte, Megabyte, 1073.741824},
+		{Gigabyte, Mebibyte, 953.67431640625},
+		{Kibibyte, Byte, 1024},
+		{Mebibyte, Kibibyte, 1024},
+		{Tebibyte, Gibibyte, 1024},
+		{Terabyte, Gigabyte, 1000},
+		{Megabyte, Kilobyte, 1000},
+		{Byte, Bit, 8},
+	}
+	for _, tt := range tests {
+		if got := tt.s.In(tt.unit); !nearlyEqual(got, tt.want) {
+			t.Errorf("%v.In(%v) = %v, want %v", tt.s, tt.unit, got, tt.want)
+		}
+	}
+	if got := (2 * Kibibyte).String(); got != "2048 B" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestTemperature(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       Temperature
+		k, c, f float64 // expected kelvin, Celsius and Fahrenheit
+	}{
+		{"body", Celsius(37.5), 310.65, 37.5, 99.5},
+		{"-40", Fahrenheit(-40), 233.15, -40, -40},
+		{"-40C", Celsius(-40), 233.15, -40, -40},
+		{"freezing", Fahrenheit(32), 273.15, 0, 32},
+		{"boiling", Celsius(100), 373.15, 100, 212},
+		{"absolute zero", AbsoluteZero, 0, -273.15, -459.67},
+		{"kelvin", Kelvin(300), 300, 26.85, 80.33},
+	}
+	for _, tt := range tests {
+		if got := tt.t.Kelvin(); math.Abs(got-tt.k) > 1e-9 {
+			t.Errorf("%s: Kelvin = %v, want %v", tt.name, got, tt.k)
+		}
+		if got := tt.t.Celsius(); math.Abs(got-tt.c) > 1e-9 {
+			t.Errorf("%s: Celsius = %v, want %v", tt.name, got, tt.c)
+		}
+		if got := tt.t.Fahrenheit(); math.Abs(got-tt.f) > 1e-9 {
+			t.Errorf("%s: Fahrenheit = %v, want %v", tt.name, got, tt.f)
+		}
+	}
+	if got := Celsius(0).String(); got != "273.15 K" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestTypedMatchesRegistry(t *testing.T) {
+	// The typed quantities and the registry share their sizes, so
+	// converting either way agrees.
+	tests := []struct {
+		typed    float64
+		v        float64
+		from, to string
+	}{
+		{(10 * Kilometre).In(Mile), 10, "km", "mi"},
+		{(3 * Stone).In(Kilogram), 3, "st", "kg"},
+		{(2 * Week).In(Hour), 2, "wk", "h"},
+		{(5 * Gibibyte).In(Megabyte), 5, "GiB", "MB"},
+		{Celsius(37.5).Fahrenheit(), 37.5, "C", "F"},
+		{Fahrenheit(98.6).Celsius(), 98.6, "F", "C"},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.v, tt.from, tt.to)
+		if err != nil || !nearlyEqual(got, tt.typed) {
+			t.Errorf("Convert(%v, %s, %s) = %v, %v; typed quantities give %v", tt.v, tt.from, tt.to, got, err, tt.typed)
+		}
+	}
+}
+
+func TestBaseMatchesTyped(t *testing.T) {
+	mi, _ := Lookup("mi")
+	if got := Length(Quantity{Value: 2, Unit: mi}.Base()); !nearlyEqual(float64(got), float64(2*Mile)) {
+		t.Errorf("Length(2 mi Base) = %v, want %v", got, 2*Mile)
+	}
+	f, _ := Lookup("F")
+	if got := Temperature(Quantity{Value: 98.6, Unit: f}.Base()); !nearlyEqual(float64(got), float64(Fahrenheit(98.6))) {
+		t.Errorf("Temperature(98.6 F Base) = %v, want %v", got, Fahrenheit(98.6))
+	}
+}
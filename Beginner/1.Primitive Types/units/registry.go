@@ -0,0 +1,120 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Registry is a set of units that can be looked up by symbol or name.
+// It is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	units    []*Unit
+	bySymbol map[string]*Unit // as written
+	byName   map[string]*Unit // lower case
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{bySymbol: map[string]*Unit{}, byName: map[string]*Unit{}}
+}
+
+// Register adds u to r. It fails if u has no symbol or a zero Scale, or
+// if its symbol or any of its names is already taken.
+func (r *Registry) Register(u Unit) error {
+	if u.Symbol == "" || u.Scale == 0 {
+		return fmt.Errorf("units: invalid unit %q", u.Symbol)
+	}
+	names := make([]string, len(u.Names))
+	for i, n := range u.Names {
+		names[i] = strings.ToLower(n)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.bySymbol[u.Symbol]; ok {
+		return fmt.Errorf("units: %q is already registered", u.Symbol)
+	}
+	for _, n := range names {
+		if _, ok := r.byName[n]; ok {
+			return fmt.Errorf("units: %q is already registered", n)
+		}
+	}
+	p := &u
+	r.units = append(r.units, p)
+	r.bySymbol[u.Symbol] = p
+	for _, n := range names {
+		r.byName[n] = p
+	}
+	return nil
+}
+
+// mustRegister registers units and panics on failure. It is for the
+// built in tables.
+func (r *Registry) mustRegister(units ...Unit) {
+	for _, u := range units {
+		if err := r.Register(u); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Lookup finds a unit by its symbol, or by one of its names ignoring
+// case: "km", "kilometre" and "Kilometers" are the same unit. Symbols
+// must match exactly, since case is what tells them apart: "M" is not
+// "m", and "MB" is a thousand times "mB" would be.
+func (r *Registry) Lookup(name string) (*Unit, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if u, ok := r.bySymbol[name]; ok {
+		return u, nil
+	}
+	if u, ok := r.byName[strings.ToLower(name)]; ok {
+		return u, nil
+	}
+	return nil, &UnknownUnitError{Name: name}
+}
+
+// Convert converts v from the unit named from to the unit named to.
+func (r *Registry) Convert(v float64, from, to string) (float64, error) {
+	f, err := r.Lookup(from)
+	if err != nil {
+		return 0, err
+	}
+	t, err := r.Lookup(to)
+	if err != nil {
+		return 0, err
+	}
+	q, err := Quantity{Value: v, Unit: f}.In(t)
+	return q.Value, err
+}
+
+// Units returns the units of dimension d in the order they were
+// registered, or all units if d is zero.
+func (r *Registry) Units(d Dimension) []*Unit {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var list []*Unit
+	for _, u := range r.units {
+		if d == 0 || u.Dimension == d {
+			list = append(list, u)
+		}
+	}
+	return list
+}
+
+// Default is the registry used by the package level functions. It
+// contains the units listed in builtin.go.
+var Default = newDefault()
+
+// Register adds u to Default.
+func Register(u Unit) error { return Default.Register(u) }
+
+// Lookup finds a unit in Default.
+func Lookup(name string) (*Unit, error) { return Default.Lookup(name) }
+
+// Convert converts v between two units in Default.
+func Convert(v float64, from, to string) (float64, error) {
+	return Default.Convert(v, from, to)
+}
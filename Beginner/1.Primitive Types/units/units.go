@@ -0,0 +1,127 @@
+// Package units converts quantities between units of measurement.
+//
+// Every unit belongs to a Dimension and is defined by how to get from it
+// to the dimension's base unit:
+//
+//	base = (value + Offset) * Scale
+//
+// Most units are linear and have no Offset (1 km is 1000 m). Temperature
+// scales are affine: 0 °C is 273.15 K, so Celsius has Offset 273.15.
+// Converting with arithmetic written out by hand is easy to get wrong;
+// tempC*(9/5)+32 adds 32 to tempC, because 9/5 is integer division and
+// is 1.
+//
+// Units are kept in a Registry. Default has the common units of
+// temperature, length, mass, time and data size, and more can be added
+// with Register. The typed quantities Temperature, Length, Mass, Duration
+// and DataSize are for code that knows what it is measuring.
+package units
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Dimension is a kind of quantity. Only units of the same dimension can
+// be converted to one another.
+type Dimension int
+
+const (
+	DimTemperature Dimension = iota + 1 // base unit kelvin
+	DimLength                           // base unit metre
+	DimMass                             // base unit kilogram
+	DimTime                             // base unit second
+	DimDataSize                         // base unit byte
+)
+
+var dimensionNames = map[Dimension]string{
+	DimTemperature: "temperature",
+	DimLength:      "length",
+	DimMass:        "mass",
+	DimTime:        "time",
+	DimDataSize:    "data size",
+}
+
+func (d Dimension) String() string {
+	if name, ok := dimensionNames[d]; ok {
+		return name
+	}
+	return "Dimension(" + strconv.Itoa(int(d)) + ")"
+}
+
+// Unit is a unit of measurement.
+type Unit struct {
+	Symbol    string   // e.g. "km"; matched exactly
+	Names     []string // other spellings, e.g. "kilometre"; matched ignoring case
+	Dimension Dimension
+	Scale     float64 // size of the unit in base units
+	Offset    float64 // added before scaling; zero for all but affine units
+}
+
+// toBase converts v in u to the base unit.
+func (u *Unit) toBase(v float64) float64 {
+	return (v + u.Offset) * u.Scale
+}
+
+// fromBase converts v in the base unit to u.
+func (u *Unit) fromBase(v float64) float64 {
+	return v/u.Scale - u.Offset
+}
+
+// Quantity is an amount of some unit.
+type Quantity struct {
+	Value float64
+	Unit  *Unit
+}
+
+// Base returns q in the base unit of its dimension, which is how the
+// typed quantities store it: Length(q.Base()) if q is a length.
+func (q Quantity) Base() float64 {
+	return q.Unit.toBase(q.Value)
+}
+
+// In converts q to the unit to, which must be of the same dimension.
+func (q Quantity) In(to *Unit) (Quantity, error) {
+	if q.Unit.Dimension != to.Dimension {
+		return Quantity{}, &DimensionMismatchError{From: q.Unit, To: to}
+	}
+	if q.Unit == to {
+		return q, nil
+	}
+	return Quantity{Value: to.fromBase(q.Base()), Unit: to}, nil
+}
+
+func (q Quantity) String() string {
+	return q.Format(-1)
+}
+
+// Format formats q with prec significant digits, or as many as are needed
+// to represent it exactly if prec is -1. A Quantity without a Unit, such
+// as the zero Quantity, is formatted as its bare value.
+func (q Quantity) Format(prec int) string {
+	v := strconv.FormatFloat(q.Value, 'g', prec, 64)
+	if q.Unit == nil {
+		return v
+	}
+	return v + " " + q.Unit.Symbol
+}
+
+// UnknownUnitError is returned for a unit that is not in the registry.
+type UnknownUnitError struct {
+	Name string
+}
+
+func (e *UnknownUnitError) Error() string {
+	return fmt.Sprintf("units: unknown unit %q", e.Name)
+}
+
+// DimensionMismatchError is returned when converting between units of
+// different dimensions, such as metres and kilograms.
+type DimensionMismatchError struct {
+	From, To *Unit
+}
+
+func (e *DimensionMismatchError) Error() string {
+	return fmt.Sprintf("units: cannot convert %s (%v) to %s (%v)",
+		e.From.Symbol, e.From.Dimension, e.To.Symbol, e.To.Dimension)
+}
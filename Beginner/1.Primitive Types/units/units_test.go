@@ -0,0 +1,212 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct{ name, symbol string }{
+		{"km", "km"},
+		{"kilometre", "km"},
+		{"Kilometers", "km"},
+		{"KILOGRAM", "kg"},
+		{"m", "m"},
+		{"mm", "mm"},
+		{"MB", "MB"},
+		{"C", "C"},
+		{"c", "C"},
+		{"°c", "C"},
+		{"f", "F"},
+		{"t", "t"},
+		{"Dog Years", "dogyr"},
+	}
+	for _, tt := range tests {
+		u, err := Lookup(tt.name)
+		if err != nil {
+			t.Errorf("Lookup(%q): %v", tt.name, err)
+			continue
+		}
+		if u.Symbol != tt.symbol {
+			t.Errorf("Lookup(%q) = %s, want %s", tt.name, u.Symbol, tt.symbol)
+		}
+	}
+}
+
+func TestLookupSymbolCase(t *testing.T) {
+	// Symbols differ by case alone, so they must not be folded: M would be
+	// mega, G giga and T tera, not metre, gram and tonne.
+	for _, name := range []string{"M", "G", "T", "MM", "Km", "KG", "mb", "kb", "S", "H"} {
+		var unknown *UnknownUnitError
+		if u, err := Lookup(name); !errors.As(err, &unknown) {
+			t.Errorf("Lookup(%q) = %v, %v; want an *UnknownUnitError", name, u, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in     string
+		value  float64
+		symbol string
+	}{
+		{"37.5C", 37.5, "C"},
+		{"37.5c", 37.5, "C"},
+		{"10 km", 10, "km"},
+		{"-40 °F", -40, "F"},
+		{"1.5e3 m", 1500, "m"},
+		{"3 dog years", 3, "dogyr"},
+		{"2e3bit", 2000, "bit"},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if q.Value != tt.value || q.Unit.Symbol != tt.symbol {
+			t.Errorf("Parse(%q) = %v %s, want %v %s", tt.in, q.Value, q.Unit.Symbol, tt.value, tt.symbol)
+		}
+	}
+	for _, in := range []string{"", "km", "10", "10 M", "1 G", "1 T", "5 MM"} {
+		if q, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", in, q)
+		}
+	}
+}
+
+// nearlyEqual reports whether a and b agree to within a relative error of 1e-9.
+func nearlyEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	// Converting from a to b and back gives the value back, for every
+	// pair of units of the same dimension.
+	cfg := &quick.Config{MaxCount: 200, Rand: rand.New(rand.NewSource(1))}
+	units := Default.Units(0)
+	for _, a := range units {
+		for _, b := range units {
+			if a.Dimension != b.Dimension {
+				continue
+			}
+			roundTrip := func(v float64) bool {
+				v = math.Mod(v, 1e6)
+				there, err := Quantity{Value: v, Unit: a}.In(b)
+				if err != nil {
+					return false
+				}
+				back, err := there.In(a)
+				return err == nil && nearlyEqual(back.Value, v)
+			}
+			if err := quick.Check(roundTrip, cfg); err != nil {
+				t.Errorf("%s -> %s -> %s: %v", a.Symbol, b.Symbol, a.Symbol, err)
+			}
+		}
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	// Every symbol and name, in any case for names, parses back to its unit.
+	cfg := &quick.Config{MaxCount: 50, Rand: rand.New(rand.NewSource(2))}
+	for _, u := range Default.Units(0) {
+		spellings := []string{u.Symbol}
+		for _, n := range u.Names {
+			spellings = append(spellings, n, strings.ToUpper(n), strings.ToLower(n))
+		}
+		for _, sp := range spellings {
+			sp := sp
+			check := func(v float64) bool {
+				v = math.Mod(v, 1e6)
+				q, err := Parse(strconv.FormatFloat(v, 'g', -1, 64) + " " + sp)
+				return err == nil && q.Unit == u && q.Value == v
+			}
+			if err := quick.Check(check, cfg); err != nil {
+				t.Errorf("%q: %v", sp, err)
+			}
+		}
+	}
+}
+
+func TestConvertDimensionMismatch(t *testing.T) {
+	var mismatch *DimensionMismatchError
+	if _, err := Convert(1, "km", "kg"); !errors.As(err, &mismatch) {
+		t.Errorf("got %v, want a *DimensionMismatchError", err)
+	}
+}
+
+func TestConvertKnownValues(t *testing.T) {
+	tests := []struct {
+		v        float64
+		from, to string
+		want     float64
+		tol      float64 // largest absolute error allowed
+	}{
+		// Float arithmetic gives 99.49999999999994 for the first, so
+		// compare with a tolerance.
+		{37.5, "C", "F", 99.5, 1e-9},
+		{-40, "F", "C", -40, 1e-9},
+		{-40, "C", "F", -40, 1e-9},
+		{100, "C", "K", 373.15, 1e-9},
+		{0, "K", "C", -273.15, 1e-9},
+		{212, "F", "C", 100, 1e-9},
+		{491.67, "R", "F", 32, 1e-9},
+		{10, "km", "mi", 6.2137, 1e-4},
+		{1, "mi", "km", 1.609344, 1e-12},
+		{1, "ft", "in", 12, 1e-12},
+		{1, "nmi", "m", 1852, 0},
+		{1, "lb", "kg", 0.45359237, 0},
+		{1, "st", "lb", 14, 1e-12},
+		{1, "t", "g", 1e6, 0},
+		{1, "d", "h", 24, 0},
+		{1, "yr", "d", 365.25, 0},
+		{7, "dogyr", "yr", 1, 1e-12},
+		{1, "GiB", "MB", 1073.741824, 1e-9},
+		{1, "GB", "MiB", 953.67431640625, 1e-9},
+		{8, "bit", "B", 1, 0},
+		{1, "TiB", "KiB", 1 << 30, 0},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.v, tt.from, tt.to)
+		if err != nil {
+			t.Errorf("Convert(%v, %s, %s): %v", tt.v, tt.from, tt.to, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > tt.tol {
+			t.Errorf("Convert(%v, %s, %s) = %v, want %v", tt.v, tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	var unknown *UnknownUnitError
+	if _, err := Convert(1, "km", "furlong"); !errors.As(err, &unknown) || unknown.Name != "furlong" {
+		t.Errorf("unknown unit: got %v, want an *UnknownUnitError for furlong", err)
+	}
+}
+
+func TestQuantityFormat(t *testing.T) {
+	km, _ := Lookup("km")
+	tests := []struct {
+		q    Quantity
+		prec int
+		want string
+	}{
+		{Quantity{Value: 6.21371192237334, Unit: km}, -1, "6.21371192237334 km"},
+		{Quantity{Value: 6.21371192237334, Unit: km}, 3, "6.21 km"},
+		{Quantity{Value: 1500, Unit: km}, 2, "1.5e+03 km"},
+		{Quantity{Value: 2.5}, -1, "2.5"},
+		{Quantity{}, -1, "0"},
+	}
+	for _, tt := range tests {
+		if got := tt.q.Format(tt.prec); got != tt.want {
+			t.Errorf("Format(%d) = %q, want %q", tt.prec, got, tt.want)
+		}
+	}
+	if got := (Quantity{}).String(); got != "0" {
+		t.Errorf("Quantity{}.String() = %q, want %q", got, "0")
+	}
+}
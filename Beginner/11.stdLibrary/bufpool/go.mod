@@ -0,0 +1,3 @@
+module github.com/learning-go-book/bufpool
+
+go 1.21
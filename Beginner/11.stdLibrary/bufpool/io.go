@@ -0,0 +1,63 @@
+package bufpool
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+// copyBufferSize matches the buffer io.Copy allocates on every call.
+const copyBufferSize = 32 * 1024
+
+// CopyPooled is io.Copy with a buffer taken from Default instead of a new
+// 32 KiB allocation. As with io.Copy, the buffer is not used at all when
+// src implements io.WriterTo or dst implements io.ReaderFrom.
+func CopyPooled(dst io.Writer, src io.Reader) (int64, error) {
+	buf := Get(copyBufferSize)
+	defer Put(buf)
+	return io.CopyBuffer(dst, src, *buf)
+}
+
+// readerPool holds bufio.Readers of the default size.
+var readerPool = sync.Pool{
+	New: func() any { return bufio.NewReader(nil) },
+}
+
+// GetReader returns a bufio.Reader reading from r, reusing one given
+// back with PutReader when possible.
+func GetReader(r io.Reader) *bufio.Reader {
+	br := readerPool.Get().(*bufio.Reader)
+	br.Reset(r)
+	return br
+}
+
+// PutReader gives br back for reuse. Any buffered data is discarded,
+// and br must not be used afterwards.
+func PutReader(br *bufio.Reader) {
+	// Drop the reference to the underlying reader so the pool does not keep it alive.
+	br.Reset(nil)
+	readerPool.Put(br)
+}
+
+// CountLetters is countLetters from ../notes.go with its 2048 byte buffer
+// taken from the pool, so that calling it in a loop does not allocate a
+// new buffer each time.
+func CountLetters(r io.Reader) (map[string]int, error) {
+	buf := Get(2048)
+	defer Put(buf)
+	out := map[string]int{}
+	for {
+		n, err := r.Read(*buf)
+		for _, b := range (*buf)[:n] {
+			if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') {
+				out[string(b)]++
+			}
+		}
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package bufpool
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// plainWriter hides any ReaderFrom method of the writer it wraps, so
+// that a copy to it has to go through the copy buffer.
+type plainWriter struct{ w io.Writer }
+
+func (p plainWriter) Write(b []byte) (int, error) { return p.w.Write(b) }
+
+// plainReader does the same for WriterTo.
+type plainReader struct{ r io.Reader }
+
+func (p plainReader) Read(b []byte) (int, error) { return p.r.Read(b) }
+
+func TestCopyPooled(t *testing.T) {
+	in := strings.Repeat("0123456789", 10000) // several copy buffers long
+	for name, src := range map[string]func() io.Reader{
+		"WriterTo":     func() io.Reader { return strings.NewReader(in) },
+		"plain":        func() io.Reader { return plainReader{strings.NewReader(in)} },
+		"OneByte":      func() io.Reader { return iotest.OneByteReader(strings.NewReader(in[:3000])) },
+		"DataErr":      func() io.Reader { return iotest.DataErrReader(strings.NewReader(in)) },
+		"HalfReader":   func() io.Reader { return iotest.HalfReader(strings.NewReader(in)) },
+		"Limited":      func() io.Reader { return io.LimitReader(strings.NewReader(in), 12345) },
+	} {
+		var out bytes.Buffer
+		n, err := CopyPooled(plainWriter{&out}, src())
+		want, _ := io.ReadAll(src())
+		if err != nil || n != int64(len(want)) || out.String() != string(want) {
+			t.Errorf("%s: copied %d bytes, %v; want %d", name, n, err, len(want))
+		}
+	}
+}
+
+func TestCopyPooledErrors(t *testing.T) {
+	readErr := errors.New("read failed")
+	n, err := CopyPooled(io.Discard, io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(readErr)))
+	if n != 3 || err != readErr {
+		t.Errorf("read error: copied %d, %v; want 3, %v", n, err, readErr)
+	}
+	n, err = CopyPooled(io.Discard, iotest.TimeoutReader(strings.NewReader("abc")))
+	if n != 3 || err != iotest.ErrTimeout {
+		t.Errorf("timeout: copied %d, %v; want 3, %v", n, err, iotest.ErrTimeout)
+	}
+	writeErr := errors.New("disk full")
+	n, err = CopyPooled(failingWriter{writeErr}, plainReader{strings.NewReader("abc")})
+	if n != 0 || err != writeErr {
+		t.Errorf("write error: copied %d, %v; want 0, %v", n, err, writeErr)
+	}
+}
+
+type failingWriter struct{ err error }
+
+func (f failingWriter) Write([]byte) (int, error) { return 0, f.err }
+
+func TestCopyPooledAllocs(t *testing.T) {
+	data := []byte(strings.Repeat("x", 100000))
+	src := &bytes.Reader{}
+	dst := plainWriter{io.Discard}
+	copyAllocs := testing.AllocsPerRun(50, func() {
+		src.Reset(data)
+		io.Copy(dst, plainReader{src})
+	})
+	pooledAllocs := testing.AllocsPerRun(50, func() {
+		src.Reset(data)
+		CopyPooled(dst, plainReader{src})
+	})
+	// io.Copy makes a 32 KiB buffer every time; CopyPooled only boxes
+	// its arguments. sync.Pool may drop buffers at any time, so compare
+	// rather than insist on an exact count.
+	if pooledAllocs >= copyAllocs {
+		t.Errorf("CopyPooled made %v allocations per copy, io.Copy %v", pooledAllocs, copyAllocs)
+	}
+}
+
+func TestGetReader(t *testing.T) {
+	br := GetReader(strings.NewReader("first line\nleft over"))
+	if line, err := br.ReadString('\n'); err != nil || line != "first line\n" {
+		t.Fatalf("ReadString = %q, %v", line, err)
+	}
+	PutReader(br) // with "left over" still buffered
+
+	for i := 0; i < 10; i++ {
+		br := GetReader(iotest.OneByteReader(strings.NewReader("second")))
+		got, err := io.ReadAll(br)
+		if err != nil || string(got) != "second" {
+			t.Fatalf("a reused reader read %q, %v; want only the new input", got, err)
+		}
+		PutReader(br)
+	}
+
+	// The reader passes on errors from what it reads.
+	br = GetReader(iotest.ErrReader(io.ErrUnexpectedEOF))
+	defer PutReader(br)
+	if _, err := br.ReadByte(); err != io.ErrUnexpectedEOF {
+		t.Errorf("ReadByte = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestGetReaderContract(t *testing.T) {
+	content := []byte(strings.Repeat("pooled bufio.Reader ", 500))
+	br := GetReader(bytes.NewReader(content))
+	defer PutReader(br)
+	if err := iotest.TestReader(br, content); err != nil {
+		t.Error(err)
+	}
+}
+
+// The two benchmarks below copy 1 MiB through a plain reader and writer,
+// so that both have to use a copy buffer: io.Copy makes one each time,
+// CopyPooled takes it from the pool.
+
+func benchmarkCopy(b *testing.B, copy func(io.Writer, io.Reader) (int64, error)) {
+	data := make([]byte, 1<<20)
+	src := &bytes.Reader{}
+	dst := plainWriter{io.Discard}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		src.Reset(data)
+		if _, err := copy(dst, plainReader{src}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIOCopy(b *testing.B)     { benchmarkCopy(b, io.Copy) }
+func BenchmarkCopyPooled(b *testing.B) { benchmarkCopy(b, CopyPooled) }
+
+func BenchmarkGetReader(b *testing.B) {
+	src := strings.NewReader("line\n")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		src.Reset("line\n")
+		br := GetReader(src)
+		br.ReadString('\n')
+		PutReader(br)
+	}
+}
@@ -0,0 +1,86 @@
+// Package bufpool is the "pool of buffers" suggested in the io notes in
+// ../notes.go: instead of making a new []byte every time a function reads
+// from an io.Reader, a buffer is taken from a pool and given back when
+// the function is done with it.
+package bufpool
+
+import (
+	"math/bits"
+	"sync"
+)
+
+const (
+	minShift = 9  // smallest size class is 512 bytes
+	maxShift = 20 // largest size class is 1 MiB
+)
+
+// BufferPool hands out byte slices in power of two size classes from
+// 512 bytes to 1 MiB, each class backed by its own sync.Pool.
+// Larger requests are allocated directly and are not pooled.
+//
+// The pools store *[]byte rather than []byte, because putting a slice
+// header into an interface would itself allocate.
+//
+// The zero value is ready to use, and a BufferPool is safe for
+// concurrent use.
+type BufferPool struct {
+	classes [maxShift - minShift + 1]sync.Pool
+}
+
+// Default is the pool used by the package level helpers.
+var Default = new(BufferPool)
+
+// class returns the index of the smallest size class holding n bytes,
+// or -1 if n is too large to be pooled.
+func class(n int) int {
+	if n <= 1<<minShift {
+		return 0
+	}
+	shift := bits.Len(uint(n - 1))
+	if shift > maxShift {
+		return -1
+	}
+	return shift - minShift
+}
+
+// Get returns a buffer of length n. Its contents are undefined.
+// Call Put when done with it. Like make, Get panics if n is negative.
+func (p *BufferPool) Get(n int) *[]byte {
+	if n < 0 {
+		panic("bufpool: negative buffer size")
+	}
+	c := class(n)
+	if c < 0 {
+		b := make([]byte, n)
+		return &b
+	}
+	if v := p.classes[c].Get(); v != nil {
+		b := v.(*[]byte)
+		*b = (*b)[:n]
+		return b
+	}
+	b := make([]byte, n, 1<<(c+minShift))
+	return &b
+}
+
+// Put returns b to the pool. b must not be used afterwards.
+// Buffers whose capacity is not one of the size classes, such as
+// slices not obtained from Get, are dropped.
+func (p *BufferPool) Put(b *[]byte) {
+	if b == nil {
+		return
+	}
+	size := cap(*b)
+	c := class(size)
+	if c < 0 || size != 1<<(c+minShift) {
+		return
+	}
+	*b = (*b)[:0]
+	p.classes[c].Put(b)
+}
+
+// Get takes a buffer of length n from Default.
+func Get(n int) *[]byte { return Default.Get(n) }
+
+// Put gives b back to Default.
+func Put(b *[]byte) { Default.Put(b) }
@@ -0,0 +1,44 @@
+package iox
+
+import (
+	"context"
+	"io"
+)
+
+// ContextReader fails every Read with ctx.Err() once ctx is done.
+// A Read already blocked in the underlying reader is not interrupted;
+// the error is returned by the next call.
+type ContextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// NewContextReader returns a ContextReader reading from r.
+func NewContextReader(ctx context.Context, r io.Reader) *ContextReader {
+	return &ContextReader{ctx: ctx, r: r}
+}
+
+func (c *ContextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// ContextWriter fails every Write with ctx.Err() once ctx is done.
+type ContextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+// NewContextWriter returns a ContextWriter writing to w.
+func NewContextWriter(ctx context.Context, w io.Writer) *ContextWriter {
+	return &ContextWriter{ctx: ctx, w: w}
+}
+
+func (c *ContextWriter) Write(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.w.Write(p)
+}
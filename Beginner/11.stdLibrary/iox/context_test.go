@@ -0,0 +1,85 @@
+package iox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestContextReader(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 100))
+	r := NewContextReader(context.Background(), iotest.HalfReader(bytes.NewReader(content)))
+	if err := iotest.TestReader(r, content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContextReaderCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewContextReader(ctx, iotest.OneByteReader(strings.NewReader("abcdef")))
+	buf := make([]byte, 4)
+	if n, err := r.Read(buf); n != 1 || err != nil {
+		t.Fatalf("Read before cancel = %d, %v", n, err)
+	}
+	cancel()
+	for i := 0; i < 2; i++ {
+		if n, err := r.Read(buf); n != 0 || !errors.Is(err, context.Canceled) {
+			t.Errorf("Read after cancel = %d, %v; want 0, context.Canceled", n, err)
+		}
+	}
+}
+
+func TestContextReaderDeadline(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	got, err := io.ReadAll(NewContextReader(ctx, strings.NewReader("never read")))
+	if len(got) != 0 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ReadAll = %q, %v; want nothing and context.DeadlineExceeded", got, err)
+	}
+}
+
+func TestContextReaderErrors(t *testing.T) {
+	// Errors from the underlying reader pass through unchanged.
+	r := NewContextReader(context.Background(), iotest.TimeoutReader(strings.NewReader("abc")))
+	if _, err := io.ReadAll(r); err != iotest.ErrTimeout {
+		t.Errorf("ReadAll error = %v, want %v", err, iotest.ErrTimeout)
+	}
+	errBoom := errors.New("boom")
+	r = NewContextReader(context.Background(), iotest.ErrReader(errBoom))
+	if _, err := r.Read(make([]byte, 1)); err != errBoom {
+		t.Errorf("Read error = %v, want %v", err, errBoom)
+	}
+}
+
+func TestContextWriter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var buf bytes.Buffer
+	w := NewContextWriter(ctx, &buf)
+	if n, err := w.Write([]byte("before")); n != 6 || err != nil {
+		t.Fatalf("Write before cancel = %d, %v", n, err)
+	}
+	cancel()
+	if n, err := w.Write([]byte("after")); n != 0 || !errors.Is(err, context.Canceled) {
+		t.Errorf("Write after cancel = %d, %v; want 0, context.Canceled", n, err)
+	}
+	if buf.String() != "before" {
+		t.Errorf("writer received %q, want only %q", buf.String(), "before")
+	}
+
+	// io.Copy stops at the first failed Write.
+	_, err := io.Copy(w, strings.NewReader("more"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("io.Copy error = %v, want context.Canceled", err)
+	}
+
+	// Errors from the underlying writer pass through unchanged.
+	w = NewContextWriter(context.Background(), &fullWriter{limit: 2})
+	if n, err := w.Write([]byte("abc")); n != 2 || err != errWriterFull {
+		t.Errorf("short write = %d, %v; want 2, %v", n, err, errWriterFull)
+	}
+}
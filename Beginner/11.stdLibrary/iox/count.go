@@ -0,0 +1,55 @@
+// Package iox is a toolkit of io.Reader and io.Writer wrappers, following
+// on from ../1.go. Every wrapper is itself an io.Reader or io.Writer, so
+// they can be chained:
+//
+//	h := iox.NewHashReader(f)
+//	c := iox.NewCountingReader(iox.NewContextReader(ctx, h))
+//	io.Copy(dst, c)
+//	fmt.Println(c.N(), hex.EncodeToString(h.Sum()))
+package iox
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// CountingReader counts the bytes read through it.
+type CountingReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+// NewCountingReader returns a CountingReader reading from r.
+func NewCountingReader(r io.Reader) *CountingReader {
+	return &CountingReader{r: r}
+}
+
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// N returns the number of bytes read so far. It is safe to call while
+// another goroutine is reading.
+func (c *CountingReader) N() int64 { return c.n.Load() }
+
+// CountingWriter counts the bytes written through it.
+type CountingWriter struct {
+	w io.Writer
+	n atomic.Int64
+}
+
+// NewCountingWriter returns a CountingWriter writing to w.
+func NewCountingWriter(w io.Writer) *CountingWriter {
+	return &CountingWriter{w: w}
+}
+
+func (c *CountingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// N returns the number of bytes written so far.
+func (c *CountingWriter) N() int64 { return c.n.Load() }
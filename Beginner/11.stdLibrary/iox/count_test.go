@@ -0,0 +1,105 @@
+package iox
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCountingReader(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 100))
+	for name, r := range map[string]io.Reader{
+		"plain":   bytes.NewReader(content),
+		"OneByte": iotest.OneByteReader(bytes.NewReader(content)),
+		"Half":    iotest.HalfReader(bytes.NewReader(content)),
+	} {
+		c := NewCountingReader(r)
+		if err := iotest.TestReader(c, content); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		// TestReader reads everything at least once; N counts every pass.
+		if c.N() < int64(len(content)) {
+			t.Errorf("%s: N = %d after reading %d bytes", name, c.N(), len(content))
+		}
+	}
+}
+
+func TestCountingReaderErrors(t *testing.T) {
+	c := NewCountingReader(iotest.TimeoutReader(strings.NewReader("abcdef")))
+	buf := make([]byte, 4)
+	if n, err := c.Read(buf); n != 4 || err != nil {
+		t.Fatalf("first Read = %d, %v", n, err)
+	}
+	if _, err := c.Read(buf); err != iotest.ErrTimeout {
+		t.Fatalf("second Read error = %v, want %v", err, iotest.ErrTimeout)
+	}
+	if c.N() != 4 {
+		t.Errorf("N = %d, want 4", c.N())
+	}
+
+	errBoom := errors.New("boom")
+	c = NewCountingReader(io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(errBoom)))
+	if _, err := io.ReadAll(c); err != errBoom {
+		t.Errorf("ReadAll error = %v, want %v", err, errBoom)
+	}
+	if c.N() != 3 {
+		t.Errorf("N = %d, want 3", c.N())
+	}
+}
+
+// fullWriter accepts the first limit bytes written to it and fails
+// after that.
+type fullWriter struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+var errWriterFull = errors.New("writer full")
+
+func (w *fullWriter) Write(p []byte) (int, error) {
+	if room := w.limit - w.buf.Len(); len(p) > room {
+		w.buf.Write(p[:room])
+		return room, errWriterFull
+	}
+	return w.buf.Write(p)
+}
+
+func TestCountingWriter(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewCountingWriter(&buf)
+	if _, err := io.Copy(c, iotest.OneByteReader(strings.NewReader("hello, world"))); err != nil {
+		t.Fatal(err)
+	}
+	if c.N() != 12 || buf.String() != "hello, world" {
+		t.Errorf("N = %d with %q written", c.N(), buf.String())
+	}
+
+	c = NewCountingWriter(&fullWriter{limit: 5})
+	n, err := c.Write([]byte("hello, world"))
+	if n != 5 || err != errWriterFull || c.N() != 5 {
+		t.Errorf("short write: Write = %d, %v and N = %d; want 5, %v and 5", n, err, c.N(), errWriterFull)
+	}
+}
+
+func TestCountingConcurrentN(t *testing.T) {
+	// N may be read while another goroutine reads; run with -race.
+	c := NewCountingReader(iotest.OneByteReader(strings.NewReader(strings.Repeat("x", 1000))))
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		io.Copy(io.Discard, c)
+	}()
+	for last := int64(0); last < 1000; {
+		n := c.N()
+		if n < last {
+			t.Fatalf("N went back from %d to %d", last, n)
+		}
+		last = n
+	}
+	wg.Wait()
+}
@@ -0,0 +1,180 @@
+package iox
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// FollowOptions configure a Follower.
+type FollowOptions struct {
+	// PollInterval is how often the file is checked for new data,
+	// truncation and rotation once everything has been read.
+	// It defaults to 250ms.
+	PollInterval time.Duration
+	// FromStart reads the file from the beginning instead of only
+	// returning data appended after Follow was called.
+	FromStart bool
+}
+
+// Follower is an io.Reader that behaves like tail -F: when it reaches the
+// end of the file it waits for more data instead of returning io.EOF.
+//
+// It notices when the file is truncated, and then reads it again from the
+// start, and when the path is rotated, meaning it now names a different
+// file (checked with os.SameFile, which compares inodes on Unix). After a
+// rotation the rest of the old file is read first, then the new one from
+// its beginning. If the path does not exist, the Follower waits for it to
+// appear.
+//
+// Read returns io.EOF once the context is done, so a bufio.Scanner on a
+// Follower stops cleanly on cancellation.
+type Follower struct {
+	ctx    context.Context
+	path   string
+	opts   FollowOptions
+	f      *os.File
+	offset int64
+}
+
+// Follow starts following path with the default options.
+func Follow(ctx context.Context, path string) (*Follower, error) {
+	return FollowWith(ctx, path, FollowOptions{})
+}
+
+// FollowWith starts following path. It fails only if path exists but
+// can not be opened.
+func FollowWith(ctx context.Context, path string, opts FollowOptions) (*Follower, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 250 * time.Millisecond
+	}
+	fl := &Follower{ctx: ctx, path: path, opts: opts}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fl, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fl.f = f
+	if !opts.FromStart {
+		if fl.offset, err = f.Seek(0, io.SeekEnd); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return fl, nil
+}
+
+// Read reads new data from the file, waiting for it if necessary.
+func (fl *Follower) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		if fl.ctx.Err() != nil {
+			return 0, io.EOF
+		}
+		if fl.f != nil {
+			n, err := fl.f.Read(p)
+			fl.offset += int64(n)
+			if n > 0 {
+				return n, nil
+			}
+			if err != nil && err != io.EOF {
+				return 0, err
+			}
+		}
+
+		// Everything has been read; see whether the file was replaced
+		// or truncated before waiting for more.
+		switched, err := fl.reopen()
+		if err != nil {
+			return 0, err
+		}
+		if switched {
+			continue
+		}
+		t := time.NewTimer(fl.opts.PollInterval)
+		select {
+		case <-t.C:
+		case <-fl.ctx.Done():
+			t.Stop()
+		}
+	}
+}
+
+// reopen switches to the file now at the path if it is a different one,
+// or rewinds the current file if it was truncated. It reports whether
+// there may be new data to read.
+func (fl *Follower) reopen() (bool, error) {
+	info, err := os.Stat(fl.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Rotated away and not created again yet: keep waiting.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if fl.f != nil {
+		cur, err := fl.f.Stat()
+		if err != nil {
+			return false, err
+		}
+		if os.SameFile(info, cur) {
+			if info.Size() < fl.offset {
+				// Truncated, as with "> file": start over.
+				fl.offset, err = fl.f.Seek(0, io.SeekStart)
+				return err == nil, err
+			}
+			return false, nil
+		}
+		// Rotated. Lines may have been appended to the old file between
+		// the read that hit its end and the rename; read them first.
+		if cur.Size() > fl.offset {
+			return true, nil
+		}
+		fl.f.Close()
+		fl.f = nil
+	}
+
+	f, err := os.Open(fl.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	fl.f = f
+	fl.offset = 0
+	return true, nil
+}
+
+// Lines calls fn with every line read, without its line ending, until
+// the context is done or fn returns an error, which Lines then returns.
+// A line is only passed on once its newline has been written, except
+// for a last unterminated line still pending when the context is done.
+func (fl *Follower) Lines(fn func(line string) error) error {
+	sc := bufio.NewScanner(fl)
+	for sc.Scan() {
+		if err := fn(sc.Text()); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// Close closes the file being followed.
+func (fl *Follower) Close() error {
+	if fl.f == nil {
+		return nil
+	}
+	err := fl.f.Close()
+	fl.f = nil
+	return err
+}
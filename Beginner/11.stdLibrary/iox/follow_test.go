@@ -0,0 +1,170 @@
+package iox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func appendFile(t *testing.T, path, s string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// collect runs Lines in the background and returns a channel of the lines.
+func collect(t *testing.T, fl *Follower) <-chan string {
+	lines := make(chan string, 100)
+	go func() {
+		defer close(lines)
+		if err := fl.Lines(func(line string) error { lines <- line; return nil }); err != nil {
+			t.Error(err)
+		}
+	}()
+	return lines
+}
+
+func expectLines(t *testing.T, lines <-chan string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		select {
+		case got := <-lines:
+			if got != w {
+				t.Fatalf("got line %q, want %q", got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %q", w)
+		}
+	}
+}
+
+func follow(t *testing.T, path string, opts FollowOptions) (*Follower, context.CancelFunc) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	opts.PollInterval = 5 * time.Millisecond
+	fl, err := FollowWith(ctx, path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cancel(); fl.Close() })
+	return fl, cancel
+}
+
+func TestFollowAppendOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	appendFile(t, path, "old\n")
+	fl, _ := follow(t, path, FollowOptions{})
+	lines := collect(t, fl)
+	appendFile(t, path, "one\ntw")
+	appendFile(t, path, "o\n")
+	expectLines(t, lines, "one", "two")
+}
+
+func TestFollowRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	appendFile(t, path, "a\n")
+	fl, _ := follow(t, path, FollowOptions{FromStart: true})
+	lines := collect(t, fl)
+	expectLines(t, lines, "a")
+
+	appendFile(t, path, "b\n")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path+".1", "c\n") // the writer has not reopened yet
+	appendFile(t, path, "d\n")
+	expectLines(t, lines, "b", "c", "d")
+}
+
+func TestFollowDrainsBeforeRotating(t *testing.T) {
+	// Drive reopen by hand, at the moment Read has just hit the end of
+	// the old file: the lines written to it since must not be skipped.
+	path := filepath.Join(t.TempDir(), "log")
+	appendFile(t, path, "a\n")
+	fl, cancel := follow(t, path, FollowOptions{FromStart: true})
+	buf := make([]byte, 64)
+	if n, err := fl.Read(buf); err != nil || string(buf[:n]) != "a\n" {
+		t.Fatalf("Read = %q, %v", buf[:n], err)
+	}
+
+	appendFile(t, path, "late\n")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path, "new\n")
+	if _, err := fl.reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read returns io.EOF once the context is done, so a lost line
+	// fails the test instead of blocking it.
+	time.AfterFunc(2*time.Second, cancel)
+	var got strings.Builder
+	for got.Len() < len("late\nnew\n") {
+		n, err := fl.Read(buf)
+		if err != nil {
+			break
+		}
+		got.Write(buf[:n])
+	}
+	if got.String() != "late\nnew\n" {
+		t.Errorf("read %q after rotation, want %q", got.String(), "late\nnew\n")
+	}
+}
+
+func TestFollowTruncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	appendFile(t, path, "first line\n")
+	fl, _ := follow(t, path, FollowOptions{FromStart: true})
+	lines := collect(t, fl)
+	expectLines(t, lines, "first line")
+	if err := os.WriteFile(path, []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	expectLines(t, lines, "x")
+}
+
+func TestFollowMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	fl, _ := follow(t, path, FollowOptions{})
+	lines := collect(t, fl)
+	appendFile(t, path, "hello\n")
+	expectLines(t, lines, "hello")
+}
+
+func TestFollowLinesStop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	appendFile(t, path, "a\nb\nc\n")
+	fl, cancel := follow(t, path, FollowOptions{FromStart: true})
+
+	errStop := errors.New("stop")
+	var seen []string
+	err := fl.Lines(func(line string) error {
+		seen = append(seen, line)
+		if line == "b" {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || strings.Join(seen, ",") != "a,b" {
+		t.Errorf("Lines = %v after %q, want errStop after a,b", err, seen)
+	}
+
+	// Cancelling ends Lines without an error.
+	cancel()
+	if err := fl.Lines(func(string) error { return nil }); err != nil {
+		t.Errorf("Lines after cancel = %v", err)
+	}
+}
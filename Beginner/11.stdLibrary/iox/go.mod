@@ -0,0 +1,3 @@
+module github.com/learning-go-book/iox
+
+go 1.21
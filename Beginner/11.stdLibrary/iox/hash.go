@@ -0,0 +1,48 @@
+package iox
+
+import (
+	"crypto/sha256"
+	"hash"
+	"io"
+)
+
+// HashReader computes the SHA-256 of everything read through it, like
+// io.TeeReader into a hash.
+type HashReader struct {
+	r io.Reader
+	h hash.Hash
+}
+
+// NewHashReader returns a HashReader reading from r.
+func NewHashReader(r io.Reader) *HashReader {
+	return &HashReader{r: r, h: sha256.New()}
+}
+
+func (h *HashReader) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	h.h.Write(p[:n]) // writing to a hash never fails
+	return n, err
+}
+
+// Sum returns the SHA-256 of the bytes read so far.
+func (h *HashReader) Sum() []byte { return h.h.Sum(nil) }
+
+// HashWriter computes the SHA-256 of everything written through it.
+type HashWriter struct {
+	w io.Writer
+	h hash.Hash
+}
+
+// NewHashWriter returns a HashWriter writing to w.
+func NewHashWriter(w io.Writer) *HashWriter {
+	return &HashWriter{w: w, h: sha256.New()}
+}
+
+func (h *HashWriter) Write(p []byte) (int, error) {
+	n, err := h.w.Write(p)
+	h.h.Write(p[:n]) // only what actually reached w
+	return n, err
+}
+
+// Sum returns the SHA-256 of the bytes written so far.
+func (h *HashWriter) Sum() []byte { return h.h.Sum(nil) }
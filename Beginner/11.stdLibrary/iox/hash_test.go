@@ -0,0 +1,85 @@
+package iox
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestHashReader(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 1000))
+	want := sha256.Sum256(content)
+	for name, r := range map[string]io.Reader{
+		"plain":   bytes.NewReader(content),
+		"OneByte": iotest.OneByteReader(bytes.NewReader(content)),
+		"Half":    iotest.HalfReader(bytes.NewReader(content)),
+		"DataErr": iotest.DataErrReader(bytes.NewReader(content)),
+	} {
+		h := NewHashReader(r)
+		got, err := io.ReadAll(h)
+		if err != nil || !bytes.Equal(got, content) {
+			t.Errorf("%s: ReadAll = %d bytes, %v", name, len(got), err)
+		}
+		if !bytes.Equal(h.Sum(), want[:]) {
+			t.Errorf("%s: Sum = %x, want %x", name, h.Sum(), want)
+		}
+	}
+
+	content = []byte("short")
+	if err := iotest.TestReader(NewHashReader(bytes.NewReader(content)), content); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHashReaderErrors(t *testing.T) {
+	// Sum covers the bytes that arrived before the error.
+	errBoom := errors.New("boom")
+	h := NewHashReader(io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(errBoom)))
+	if _, err := io.ReadAll(h); err != errBoom {
+		t.Errorf("ReadAll error = %v, want %v", err, errBoom)
+	}
+	if want := sha256.Sum256([]byte("abc")); !bytes.Equal(h.Sum(), want[:]) {
+		t.Errorf("Sum = %x, want the hash of %q", h.Sum(), "abc")
+	}
+
+	h = NewHashReader(iotest.TimeoutReader(strings.NewReader("abc")))
+	if _, err := io.ReadAll(h); err != iotest.ErrTimeout {
+		t.Errorf("ReadAll error = %v, want %v", err, iotest.ErrTimeout)
+	}
+}
+
+func TestHashWriter(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewHashWriter(&buf)
+	if _, err := io.Copy(h, iotest.OneByteReader(strings.NewReader("hello, world"))); err != nil {
+		t.Fatal(err)
+	}
+	if want := sha256.Sum256([]byte("hello, world")); !bytes.Equal(h.Sum(), want[:]) {
+		t.Errorf("Sum = %x, want %x", h.Sum(), want)
+	}
+
+	// Only the bytes that reached the writer are hashed.
+	sw := &fullWriter{limit: 5}
+	h = NewHashWriter(sw)
+	if n, err := h.Write([]byte("hello, world")); n != 5 || err != errWriterFull {
+		t.Fatalf("Write = %d, %v; want 5, %v", n, err, errWriterFull)
+	}
+	if want := sha256.Sum256([]byte("hello")); !bytes.Equal(h.Sum(), want[:]) {
+		t.Errorf("Sum after a short write = %x, want the hash of %q", h.Sum(), sw.buf.String())
+	}
+}
+
+func TestHashSumIsRunning(t *testing.T) {
+	// Sum can be called part way through without disturbing the hash.
+	h := NewHashWriter(io.Discard)
+	h.Write([]byte("hello, "))
+	h.Sum()
+	h.Write([]byte("world"))
+	if want := sha256.Sum256([]byte("hello, world")); !bytes.Equal(h.Sum(), want[:]) {
+		t.Errorf("Sum = %x, want %x", h.Sum(), want)
+	}
+}
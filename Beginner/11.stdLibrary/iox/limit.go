@@ -0,0 +1,117 @@
+package iox
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// limiter is a token bucket: it holds up to burst bytes and refills at
+// rate bytes per second.
+type limiter struct {
+	ctx    context.Context
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter panics if bytesPerSec is not positive: a rate of zero would
+// never let a byte through, and a negative one would divide by zero and
+// spin. A burst of 0 or less means one second's worth of bytes.
+func newLimiter(ctx context.Context, bytesPerSec, burst int) *limiter {
+	if bytesPerSec <= 0 {
+		panic("iox: non-positive rate for a rate limited reader or writer")
+	}
+	if burst <= 0 {
+		burst = bytesPerSec
+	}
+	return &limiter{ctx: ctx, rate: float64(bytesPerSec), burst: burst, tokens: float64(burst), last: time.Now()}
+}
+
+// take waits until n bytes (at most burst) may pass, and returns how
+// many that is.
+func (l *limiter) take(n int) (int, error) {
+	n = min(n, l.burst)
+	now := time.Now()
+	l.tokens = min(float64(l.burst), l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if missing := float64(n) - l.tokens; missing > 0 {
+		wait := time.Duration(missing / l.rate * float64(time.Second))
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-l.ctx.Done():
+			return 0, l.ctx.Err()
+		}
+		l.tokens += wait.Seconds() * l.rate
+		l.last = time.Now()
+	}
+	l.tokens -= float64(n)
+	return n, nil
+}
+
+// refund gives back n bytes taken but not used, as when a Read returns
+// less than it asked for.
+func (l *limiter) refund(n int) {
+	l.tokens = min(float64(l.burst), l.tokens+float64(n))
+}
+
+// RateLimitedReader reads at most bytesPerSec bytes per second from its
+// underlying reader on average, with bursts of up to burst bytes.
+type RateLimitedReader struct {
+	r io.Reader
+	l *limiter
+}
+
+// NewRateLimitedReader returns a RateLimitedReader reading from r.
+// bytesPerSec must be positive; a burst of 0 or less means one second's
+// worth of bytes. Waiting stops with ctx.Err() when ctx is done.
+func NewRateLimitedReader(ctx context.Context, r io.Reader, bytesPerSec, burst int) *RateLimitedReader {
+	return &RateLimitedReader{r: r, l: newLimiter(ctx, bytesPerSec, burst)}
+}
+
+func (rl *RateLimitedReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return rl.r.Read(p)
+	}
+	n, err := rl.l.take(len(p))
+	if err != nil {
+		return 0, err
+	}
+	m, err := rl.r.Read(p[:n])
+	rl.l.refund(n - m)
+	return m, err
+}
+
+// RateLimitedWriter writes at most bytesPerSec bytes per second to its
+// underlying writer on average, with bursts of up to burst bytes.
+type RateLimitedWriter struct {
+	w io.Writer
+	l *limiter
+}
+
+// NewRateLimitedWriter returns a RateLimitedWriter writing to w.
+// See NewRateLimitedReader for the meaning of the arguments.
+func NewRateLimitedWriter(ctx context.Context, w io.Writer, bytesPerSec, burst int) *RateLimitedWriter {
+	return &RateLimitedWriter{w: w, l: newLimiter(ctx, bytesPerSec, burst)}
+}
+
+// Write writes all of p, in pieces of at most burst bytes.
+func (rl *RateLimitedWriter) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		n, err := rl.l.take(len(p) - written)
+		if err != nil {
+			return written, err
+		}
+		m, err := rl.w.Write(p[written : written+n])
+		rl.l.refund(n - m)
+		written += m
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
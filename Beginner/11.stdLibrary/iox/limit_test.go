@@ -0,0 +1,71 @@
+package iox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestRateLimitedReader(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 100))
+	r := NewRateLimitedReader(context.Background(), bytes.NewReader(content), 1<<20, 64)
+	if err := iotest.TestReader(r, content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRateLimitedReaderRate(t *testing.T) {
+	// 200 bytes at 1000 bytes per second with a burst of 100: the first
+	// 100 pass at once, the rest take about 100ms.
+	r := NewRateLimitedReader(context.Background(), iotest.OneByteReader(strings.NewReader(strings.Repeat("x", 200))), 1000, 100)
+	start := time.Now()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 80*time.Millisecond {
+		t.Errorf("read 200 bytes in %v, want about 100ms", d)
+	}
+}
+
+func TestRateLimitedWriterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var buf bytes.Buffer
+	w := NewRateLimitedWriter(ctx, &buf, 10, 10)
+	time.AfterFunc(20*time.Millisecond, cancel)
+	n, err := w.Write(make([]byte, 100))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if n != 10 || buf.Len() != 10 {
+		t.Errorf("wrote %d bytes (%d arrived), want the 10 byte burst", n, buf.Len())
+	}
+}
+
+func TestNonPositiveRate(t *testing.T) {
+	for _, rate := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("rate %d did not panic", rate)
+				}
+			}()
+			NewRateLimitedReader(context.Background(), strings.NewReader(""), rate, 10)
+		}()
+	}
+}
+
+func TestNonPositiveBurst(t *testing.T) {
+	// A burst of 0 or less is one second's worth of bytes, so reading
+	// still makes progress.
+	for _, burst := range []int{0, -5} {
+		r := NewRateLimitedReader(context.Background(), strings.NewReader("hello"), 1000, burst)
+		if err := iotest.TestReader(r, []byte("hello")); err != nil {
+			t.Errorf("burst %d: %v", burst, err)
+		}
+	}
+}
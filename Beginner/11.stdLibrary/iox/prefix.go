@@ -0,0 +1,53 @@
+package iox
+
+import (
+	"bytes"
+	"io"
+)
+
+// PrefixWriter writes prefix at the start of every line, for example to
+// tag the output of several workers sharing os.Stdout. Lines may arrive
+// split over several Write calls; the prefix is still written once.
+type PrefixWriter struct {
+	w       io.Writer
+	prefix  []byte
+	midLine bool // the last Write did not end with a newline
+}
+
+// NewPrefixWriter returns a PrefixWriter writing to w.
+func NewPrefixWriter(w io.Writer, prefix string) *PrefixWriter {
+	return &PrefixWriter{w: w, prefix: []byte(prefix)}
+}
+
+// Write writes p with the prefix added. The returned count refers to
+// bytes of p, not counting the prefixes.
+func (pw *PrefixWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if !pw.midLine {
+			if _, err := pw.w.Write(pw.prefix); err != nil {
+				return written, err
+			}
+			pw.midLine = true
+		}
+		line := p
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			line = p[:i+1]
+		}
+		n, err := pw.w.Write(line)
+		written += n
+		// Only a newline that actually reached w ends the line; after a
+		// failed or short write the next Write continues it unprefixed.
+		if n > 0 {
+			pw.midLine = line[n-1] != '\n'
+		}
+		if err == nil && n < len(line) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			return written, err
+		}
+		p = p[len(line):]
+	}
+	return written, nil
+}
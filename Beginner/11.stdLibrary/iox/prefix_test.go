@@ -0,0 +1,69 @@
+package iox
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+	pw := NewPrefixWriter(&buf, "> ")
+	for _, s := range []string{"one\ntw", "o\n", "", "\n", "three"} {
+		if n, err := pw.Write([]byte(s)); n != len(s) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v", s, n, err)
+		}
+	}
+	if want := "> one\n> two\n> \n> three"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+// failingWriter writes up to n bytes, then fails with err.
+type failingWriter struct {
+	buf bytes.Buffer
+	n   int
+	err error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		m, _ := w.buf.Write(p[:w.n])
+		w.n = 0
+		return m, w.err
+	}
+	w.n -= len(p)
+	return w.buf.Write(p)
+}
+
+func TestPrefixWriterFailedNewline(t *testing.T) {
+	// The newline of "one\n" does not reach w, so the line is not over
+	// and the retry must not be prefixed.
+	errDisk := errors.New("disk full")
+	fw := &failingWriter{n: len("> one"), err: errDisk}
+	pw := NewPrefixWriter(fw, "> ")
+	n, err := pw.Write([]byte("one\n"))
+	if n != 3 || err != errDisk {
+		t.Fatalf("Write = %d, %v; want 3, %v", n, err, errDisk)
+	}
+	fw.n = 100
+	if _, err := pw.Write([]byte("\ntwo\n")); err != nil {
+		t.Fatal(err)
+	}
+	if want := "> one\n> two\n"; fw.buf.String() != want {
+		t.Errorf("got %q, want %q", fw.buf.String(), want)
+	}
+}
+
+// shortWriter accepts one byte less than it is given, without an error.
+type shortWriter struct{}
+
+func (shortWriter) Write(p []byte) (int, error) { return max(len(p)-1, 0), nil }
+
+func TestPrefixWriterShortWrite(t *testing.T) {
+	pw := NewPrefixWriter(shortWriter{}, "> ")
+	if _, err := pw.Write([]byte("hello\n")); err != io.ErrShortWrite {
+		t.Errorf("got %v, want io.ErrShortWrite", err)
+	}
+}
@@ -0,0 +1,38 @@
+package iox
+
+import (
+	"io"
+	"time"
+)
+
+// ProgressFunc is told how many bytes have gone through a
+// ProgressReader, and how many are expected in total (0 if unknown).
+type ProgressFunc func(done, total int64)
+
+// ProgressReader reports its progress to a ProgressFunc, at most once per
+// interval and always when the reader ends.
+type ProgressReader struct {
+	r        io.Reader
+	total    int64
+	done     int64
+	fn       ProgressFunc
+	interval time.Duration
+	last     time.Time
+}
+
+// NewProgressReader returns a ProgressReader reading from r. total is the
+// expected size, or 0 if it is not known. An interval of 0 reports after
+// every Read.
+func NewProgressReader(r io.Reader, total int64, interval time.Duration, fn ProgressFunc) *ProgressReader {
+	return &ProgressReader{r: r, total: total, fn: fn, interval: interval}
+}
+
+func (p *ProgressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	if err != nil || time.Since(p.last) >= p.interval {
+		p.last = time.Now()
+		p.fn(p.done, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,90 @@
+package iox
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// progressCalls records the calls made to a ProgressFunc.
+type progressCalls [][2]int64
+
+func (c *progressCalls) record(done, total int64) {
+	*c = append(*c, [2]int64{done, total})
+}
+
+func TestProgressReader(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 100))
+	var calls progressCalls
+	r := NewProgressReader(bytes.NewReader(content), int64(len(content)), 0, calls.record)
+	if err := iotest.TestReader(r, content); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) == 0 {
+		t.Fatal("ProgressFunc was never called")
+	}
+	for i, c := range calls {
+		if c[1] != int64(len(content)) {
+			t.Errorf("call %d has total %d, want %d", i, c[1], len(content))
+		}
+		if i > 0 && c[0] < calls[i-1][0] {
+			t.Errorf("done went back from %d to %d", calls[i-1][0], c[0])
+		}
+	}
+}
+
+func TestProgressReaderInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     progressCalls
+	}{
+		// Every Read is reported, including the final one that sees EOF.
+		{"every read", 0, progressCalls{{1, 0}, {2, 0}, {3, 0}, {4, 0}, {4, 0}}},
+		// The first Read and the end are always reported.
+		{"long interval", time.Hour, progressCalls{{1, 0}, {4, 0}}},
+	}
+	for _, tt := range tests {
+		var calls progressCalls
+		r := NewProgressReader(iotest.OneByteReader(strings.NewReader("abcd")), 0, tt.interval, calls.record)
+		if _, err := io.ReadAll(r); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(calls) != len(tt.want) {
+			t.Errorf("%s: calls = %v, want %v", tt.name, calls, tt.want)
+			continue
+		}
+		for i := range calls {
+			if calls[i] != tt.want[i] {
+				t.Errorf("%s: calls = %v, want %v", tt.name, calls, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestProgressReaderErrors(t *testing.T) {
+	// An error ends the reader, so it is reported even inside the interval.
+	var calls progressCalls
+	r := NewProgressReader(iotest.TimeoutReader(strings.NewReader("abcdef")), 6, time.Hour, calls.record)
+	if _, err := io.ReadAll(r); err != iotest.ErrTimeout {
+		t.Fatalf("ReadAll error = %v, want %v", err, iotest.ErrTimeout)
+	}
+	if len(calls) != 2 || calls[1] != [2]int64{6, 6} {
+		t.Errorf("calls = %v, want the first read and then 6 of 6", calls)
+	}
+
+	errBoom := errors.New("boom")
+	calls = nil
+	r = NewProgressReader(io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(errBoom)), 0, time.Hour, calls.record)
+	if _, err := io.ReadAll(r); err != errBoom {
+		t.Fatalf("ReadAll error = %v, want %v", err, errBoom)
+	}
+	if last := calls[len(calls)-1]; last != [2]int64{3, 0} {
+		t.Errorf("last call = %v, want 3 of 0", last)
+	}
+}
@@ -0,0 +1,70 @@
+package orders
+
+import (
+	"reflect"
+	"strings"
+)
+
+// field describes how one struct field appears in JSON.
+type field struct {
+	name      string // key in the JSON object
+	index     []int  // for reflect.Value.FieldByIndex
+	typ       reflect.Type
+	omitEmpty bool // tagged omitempty
+}
+
+// required reports whether the field must be present in a document.
+// Pointers may be left out, where a missing key simply means nil, and so
+// may omitempty fields, since Marshal leaves them out when they are
+// zero and a reader must accept what Marshal writes.
+func (f field) required() bool {
+	return f.typ.Kind() != reflect.Pointer && !f.omitEmpty
+}
+
+// jsonFields lists the fields of struct type t in the order they are
+// declared, following the same rules as encoding/json: unexported and
+// "-" fields are skipped, untagged embedded structs are flattened.
+func jsonFields(t reflect.Type) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := sf.Type
+		if sf.Anonymous && name == "" {
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, inner := range jsonFields(ft) {
+					inner.index = append([]int{i}, inner.index...)
+					fields = append(fields, inner)
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, field{name: name, index: []int{i}, typ: sf.Type, omitEmpty: hasOption(opts, "omitempty")})
+	}
+	return fields
+}
+
+// hasOption reports whether the comma separated tag options opts
+// include opt.
+func hasOption(opts, opt string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
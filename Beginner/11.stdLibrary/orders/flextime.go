@@ -0,0 +1,184 @@
+package orders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultLayouts are the layouts tried, in order, by a TimeParser with
+// no Layouts of its own.
+var defaultLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// DefaultTimeLayouts returns a copy of the layouts a zero TimeParser tries, for
+// building a TimeParser that extends them.
+func DefaultTimeLayouts() []string {
+	return append([]string(nil), defaultLayouts...)
+}
+
+// FlexibleTime is a time.Time that unmarshals from more than RFC 3339.
+// It accepts:
+//
+//   - a string in any of the layouts of DefaultTimeParser, which are
+//     DefaultTimeLayouts unless set otherwise, e.g. "2020-05-01 13:01:02 -0700";
+//   - such a string followed by an IANA time zone name, e.g.
+//     "2020-05-01 13:01:02 Asia/Kathmandu", which is then read in that zone;
+//   - Unix epoch seconds or milliseconds, as a JSON number or a numeric string.
+//     Values of 1e11 and above are taken as milliseconds (1e11 seconds is
+//     the year 5138), everything below as seconds.
+//
+// Programs whose feeds use other layouts set DefaultTimeParser before
+// decoding. FlexibleTime always marshals as RFC 3339, so documents
+// written by this package can be read by any other program.
+type FlexibleTime struct {
+	time.Time
+}
+
+// TimeParseError reports a value that matched none of the layouts.
+type TimeParseError struct {
+	Value   string
+	Layouts []string
+}
+
+func (e *TimeParseError) Error() string {
+	return fmt.Sprintf("cannot parse %q as a time: tried the layouts %q, Unix seconds and Unix milliseconds",
+		e.Value, e.Layouts)
+}
+
+// TimeParser parses times the way FlexibleTime does.
+// The zero TimeParser uses DefaultTimeLayouts.
+type TimeParser struct {
+	Layouts []string // layouts to try, in order
+}
+
+// DefaultTimeParser is the parser FlexibleTime unmarshals strings with,
+// and so the one that decides which layouts Order.DateOrdered accepts.
+// Its zero value uses DefaultTimeLayouts. Set it at start up, before any
+// decoding; it is read without locking.
+var DefaultTimeParser TimeParser
+
+// ParseTime parses s with a TimeParser using layouts, or the defaults
+// when none are given.
+func ParseTime(s string, layouts ...string) (time.Time, error) {
+	return TimeParser{Layouts: layouts}.Parse(s)
+}
+
+// Parse reads s with the first layout that fits it, then as Unix seconds
+// or milliseconds. Layouts come first so that all-digit layouts such as
+// "20060102" win over the epoch.
+func (p TimeParser) Parse(s string) (time.Time, error) {
+	layouts := p.Layouts
+	if len(layouts) == 0 {
+		layouts = defaultLayouts
+	}
+	s = strings.TrimSpace(s)
+
+	// Try the whole string in UTC first, then the string with its last
+	// word taken as a time zone name.
+	if t, ok := parseLayouts(s, layouts, time.UTC); ok {
+		return t, nil
+	}
+	if i := strings.LastIndexByte(s, ' '); i > 0 {
+		if loc, err := time.LoadLocation(s[i+1:]); err == nil {
+			if t, ok := parseLayouts(s[:i], layouts, loc); ok {
+				return t, nil
+			}
+		}
+	}
+	if t, ok := parseEpoch(s); ok {
+		return t, nil
+	}
+	return time.Time{}, &TimeParseError{Value: s, Layouts: layouts}
+}
+
+// parseLayouts returns the result of the first layout that parses s.
+func parseLayouts(s string, layouts []string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, s, loc)
+		if err != nil || unknownZone(layout, t) {
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// unknownZone reports whether t was parsed with a zone abbreviation that
+// time.Parse did not know. Parse accepts any abbreviation and, when it
+// cannot resolve one, gives it a zero offset, which would silently read
+// "12:00 XYZ" as noon UTC.
+func unknownZone(layout string, t time.Time) bool {
+	if !strings.Contains(layout, "MST") {
+		return false
+	}
+	name, offset := t.Zone()
+	return offset == 0 && name != "UTC" && name != "GMT"
+}
+
+// parseEpoch reads s as Unix seconds or milliseconds.
+func parseEpoch(s string) (time.Time, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return time.Time{}, false
+	}
+	if math.Abs(f) >= 1e11 {
+		ms := math.Round(f)
+		return time.UnixMilli(int64(ms)).UTC(), true
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC(), true
+}
+
+// MarshalJSON writes the time as an RFC 3339 string.
+func (ft FlexibleTime) MarshalJSON() ([]byte, error) {
+	return ft.Time.MarshalJSON()
+}
+
+// UnmarshalJSON accepts a string or a number; null leaves ft unchanged.
+func (ft *FlexibleTime) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return ft.UnmarshalText([]byte(s))
+	}
+	t, ok := parseEpoch(string(data))
+	if !ok {
+		return fmt.Errorf("cannot parse %s as a time: expected a string or a number", data)
+	}
+	ft.Time = t
+	return nil
+}
+
+// MarshalText writes the time in RFC 3339 format.
+func (ft FlexibleTime) MarshalText() ([]byte, error) {
+	return ft.Time.MarshalText()
+}
+
+// UnmarshalText parses text with DefaultTimeParser.
+func (ft *FlexibleTime) UnmarshalText(text []byte) error {
+	t, err := DefaultTimeParser.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	ft.Time = t
+	return nil
+}
@@ -0,0 +1,108 @@
+package orders
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	kathmandu, err := time.LoadLocation("Asia/Kathmandu")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	tests := []struct {
+		in      string
+		layouts []string
+		want    time.Time
+	}{
+		{"2020-05-01T13:01:02Z", nil, time.Date(2020, 5, 1, 13, 1, 2, 0, time.UTC)},
+		{"2020-05-01 13:01:02 -0700", nil, time.Date(2020, 5, 1, 20, 1, 2, 0, time.UTC)},
+		{"2020-05-01 13:01:02 UTC", nil, time.Date(2020, 5, 1, 13, 1, 2, 0, time.UTC)},
+		{"Fri, 01 May 2020 13:01:02 GMT", nil, time.Date(2020, 5, 1, 13, 1, 2, 0, time.UTC)},
+		{"2020-05-01 13:01:02 Asia/Kathmandu", nil, time.Date(2020, 5, 1, 13, 1, 2, 0, kathmandu)},
+		{"1588338062", nil, time.Date(2020, 5, 1, 13, 1, 2, 0, time.UTC)},
+		{"1588338062500", nil, time.Date(2020, 5, 1, 13, 1, 2, 5e8, time.UTC)},
+		{"20200501", []string{"20060102"}, time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.in, tt.layouts...)
+		if err != nil {
+			t.Errorf("ParseTime(%q, %q): %v", tt.in, tt.layouts, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q, %q) = %v, want %v", tt.in, tt.layouts, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimeUnknownZone(t *testing.T) {
+	for _, in := range []string{"2020-05-01 13:01:02 XYZ", "Fri, 01 May 2020 13:01:02 QQT"} {
+		_, err := ParseTime(in)
+		var pe *TimeParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("ParseTime(%q) = %v, want a TimeParseError", in, err)
+		}
+	}
+}
+
+func TestTimeParserLayouts(t *testing.T) {
+	p := TimeParser{Layouts: append(DefaultTimeLayouts(), "02/01/2006")}
+	got, err := p.Parse("31/12/2020")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// The extra layout is only known to p.
+	if _, err := ParseTime("31/12/2020"); err == nil {
+		t.Error("the default layouts accepted 31/12/2020")
+	}
+}
+
+func TestFlexibleTimeJSON(t *testing.T) {
+	var v struct{ T FlexibleTime }
+	if err := json.Unmarshal([]byte(`{"T":1588338062}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"T":"2020-05-01T13:01:02Z"}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
+
+func TestDefaultTimeParser(t *testing.T) {
+	const doc = `{"id":"1","date_ordered":"01/05/2020 13:01","customer_id":"c1","items":[]}`
+	var o Order
+	if err := json.Unmarshal([]byte(doc), &o); err == nil {
+		t.Fatalf("the default layouts read 01/05/2020 13:01 as %v", o.DateOrdered)
+	}
+
+	saved := DefaultTimeParser
+	defer func() { DefaultTimeParser = saved }()
+	DefaultTimeParser = TimeParser{Layouts: append(DefaultTimeLayouts(), "02/01/2006 15:04")}
+
+	want := time.Date(2020, 5, 1, 13, 1, 0, 0, time.UTC)
+	if err := json.Unmarshal([]byte(doc), &o); err != nil || !o.DateOrdered.Equal(want) {
+		t.Errorf("json.Unmarshal: %v, %v; want %v", o.DateOrdered, err, want)
+	}
+	o = Order{}
+	if err := UnmarshalStrict([]byte(doc), &o); err != nil || !o.DateOrdered.Equal(want) {
+		t.Errorf("UnmarshalStrict: %v, %v; want %v", o.DateOrdered, err, want)
+	}
+	r := NewNDJSONReader(strings.NewReader(doc + "\n"))
+	if o, err := r.Next(); err != nil || !o.DateOrdered.Equal(want) {
+		t.Errorf("NDJSON Reader: %v, %v; want %v", o.DateOrdered, err, want)
+	}
+	// The defaults still work alongside the added layout.
+	if err := json.Unmarshal([]byte(`"2020-05-01T13:01:00Z"`), &o.DateOrdered); err != nil || !o.DateOrdered.Equal(want) {
+		t.Errorf("RFC 3339: %v, %v", o.DateOrdered, err)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/learning-go-book/orders
+
+go 1.22
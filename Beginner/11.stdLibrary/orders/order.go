@@ -0,0 +1,17 @@
+// Package orders reads and writes the Order documents of the order
+// management example in ../notes.go.
+package orders
+
+// Order is one order placed by a customer.
+type Order struct {
+	ID          string       `json:"id"`
+	DateOrdered FlexibleTime `json:"date_ordered"`
+	CustomerID  string       `json:"customer_id"`
+	Items       []Item       `json:"items"`
+}
+
+// Item is a single line of an Order.
+type Item struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
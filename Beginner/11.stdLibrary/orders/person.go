@@ -0,0 +1,11 @@
+package orders
+
+// Person is the type marshalled and unmarshalled in ../3.go.
+// It lives here as well so that it can be decoded strictly and described
+// by a JSON Schema, just like Order.
+type Person struct {
+	Name       string `json:"name"`          // Name of the person
+	Age        int    `json:"age,omitempty"` // Age of the person; omitempty avoids marshalling if left empty
+	Sex        string `json:"sex"`           // Sex of the person
+	Occupation string `json:"-"`             // Occupation of the person; never part of the JSON
+}
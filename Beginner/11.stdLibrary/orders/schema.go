@@ -0,0 +1,144 @@
+package orders
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// schemaDraft is the JSON Schema dialect produced by Schema.
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema returns a JSON Schema describing the JSON form of v's type, as
+// read from its struct tags. The rules match DecodeStrict: pointer and
+// omitempty fields are optional, everything else is required, no other properties are
+// allowed, and null is accepted exactly where Marshal may write it: for
+// pointers, slices, maps and interfaces.
+//
+// Named struct types are put in "$defs" and referenced, so recursive
+// types produce a finite schema.
+func Schema(v any) ([]byte, error) {
+	g := schemaGen{defs: map[string]any{}}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	root := map[string]any{"$schema": schemaDraft}
+	for k, val := range g.typeSchema(t) {
+		root[k] = val
+	}
+	if len(g.defs) > 0 {
+		root["$defs"] = g.defs
+	}
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaGen collects the definitions of the struct types it has seen.
+type schemaGen struct {
+	defs map[string]any
+}
+
+var (
+	timeType         = reflect.TypeFor[time.Time]()
+	flexibleTimeType = reflect.TypeFor[FlexibleTime]()
+)
+
+// typeSchema returns the schema for t.
+func (g *schemaGen) typeSchema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		return nullable(g.typeSchema(t.Elem()))
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	if t == flexibleTimeType {
+		// Any of the accepted layouts, or Unix seconds or milliseconds.
+		return map[string]any{"type": []string{"string", "number"}}
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		// A custom decoder accepts whatever it likes; nothing can be said about it.
+		return map[string]any{}
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = map[string]any{} // placeholder, stops recursion
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	case reflect.Map:
+		return map[string]any{"type": []string{"object", "null"}, "additionalProperties": g.typeSchema(t.Elem())}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": []string{"string", "null"}, "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": []string{"array", "null"}, "items": g.typeSchema(t.Elem())}
+	case reflect.Array:
+		return map[string]any{"type": "array", "items": g.typeSchema(t.Elem()), "maxItems": t.Len()}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	// Interfaces, and anything else, accept any value.
+	return map[string]any{}
+}
+
+// nullable returns s extended to also accept null.
+func nullable(s map[string]any) map[string]any {
+	switch typ := s["type"].(type) {
+	case string:
+		s["type"] = []string{typ, "null"}
+	case []string:
+		for _, t := range typ {
+			if t == "null" {
+				return s
+			}
+		}
+		s["type"] = append(typ, "null")
+	default:
+		if len(s) == 0 {
+			return s // already accepts anything
+		}
+		return map[string]any{"anyOf": []any{s, map[string]any{"type": "null"}}}
+	}
+	return s
+}
+
+// structSchema returns the object schema for struct type t.
+func (g *schemaGen) structSchema(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	required := []string{}
+	for _, f := range jsonFields(t) {
+		props[f.name] = g.typeSchema(f.typ)
+		if f.required() {
+			required = append(required, f.name)
+		}
+	}
+	s := map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if t.Name() != "" {
+		s["title"] = t.Name()
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
@@ -0,0 +1,52 @@
+package orders
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	type Doc struct {
+		Tags  []string       `json:"tags"`
+		Attrs map[string]int `json:"attrs"`
+		Raw   []byte         `json:"raw"`
+		Count int            `json:"count,omitempty"`
+		Next  *Item          `json:"next"`
+		Note  *string        `json:"note"`
+	}
+	data, err := Schema(Doc{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var root struct {
+		Defs map[string]struct {
+			Properties map[string]map[string]any
+			Required   []string
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatal(err)
+	}
+	s := root.Defs["Doc"]
+
+	types := map[string]any{
+		"tags":  []any{"array", "null"},
+		"attrs": []any{"object", "null"},
+		"raw":   []any{"string", "null"},
+		"count": "integer",
+		"note":  []any{"string", "null"},
+	}
+	for name, want := range types {
+		if got := s.Properties[name]["type"]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s has type %v, want %v", name, got, want)
+		}
+	}
+	wantNext := []any{map[string]any{"$ref": "#/$defs/Item"}, map[string]any{"type": "null"}}
+	if got := s.Properties["next"]["anyOf"]; !reflect.DeepEqual(got, wantNext) {
+		t.Errorf("next is %v, want anyOf %v", s.Properties["next"], wantNext)
+	}
+	if want := []string{"tags", "attrs", "raw"}; !reflect.DeepEqual(s.Required, want) {
+		t.Errorf("required = %q, want %q", s.Required, want)
+	}
+}
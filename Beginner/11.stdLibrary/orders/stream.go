@@ -0,0 +1,188 @@
+package orders
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Reader yields the Orders of a JSON document one at a time.
+// Only the Order being decoded is held in memory, so a feed of any size
+// can be read with flat memory use.
+//
+// A Reader made by NewReader expects a single JSON array of orders;
+// one made by NewNDJSONReader expects newline delimited JSON, one order per line.
+type Reader struct {
+	dec     *json.Decoder
+	array   bool // input is a JSON array rather than NDJSON
+	started bool // the opening '[' has been read
+	done    bool
+
+	lines *bufio.Reader // NDJSON input
+	line  int           // number of the last NDJSON line read
+}
+
+// NewReader returns a Reader for a JSON array of orders:
+//
+//	[{"id":"1",...},{"id":"2",...}]
+func NewReader(r io.Reader) *Reader {
+	return &Reader{dec: json.NewDecoder(r), array: true}
+}
+
+// NewNDJSONReader returns a Reader for newline delimited JSON:
+//
+//	{"id":"1",...}
+//	{"id":"2",...}
+//
+// Blank lines are skipped. A line that does not hold an order is
+// reported with its line number, and the next call to Next carries on
+// with the line after it.
+func NewNDJSONReader(r io.Reader) *Reader {
+	return &Reader{lines: bufio.NewReader(r)}
+}
+
+// Next returns the next Order. It returns io.EOF once every order has
+// been read.
+func (r *Reader) Next() (Order, error) {
+	var o Order
+	if r.done {
+		return o, io.EOF
+	}
+	if !r.array {
+		return r.nextLine()
+	}
+	if !r.started {
+		// Token walks the stream one JSON token at a time; the first one
+		// has to be the '[' that opens the array.
+		if err := r.expectDelim('['); err != nil {
+			return o, err
+		}
+		r.started = true
+	}
+
+	if !r.dec.More() {
+		r.done = true
+		if err := r.expectDelim(']'); err != nil {
+			return o, err
+		}
+		// Nothing but whitespace may follow the array.
+		switch _, err := r.dec.Token(); err {
+		case io.EOF:
+			return o, io.EOF
+		case nil:
+			return o, fmt.Errorf("orders: unexpected data after array")
+		default:
+			return o, err
+		}
+	}
+
+	if err := r.dec.Decode(&o); err != nil {
+		return Order{}, err
+	}
+	return o, nil
+}
+
+// nextLine returns the order on the next line that is not blank.
+func (r *Reader) nextLine() (Order, error) {
+	for {
+		line, err := r.lines.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			if err == io.EOF {
+				r.done = true
+			}
+			return Order{}, err
+		}
+		r.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var o Order
+		if err := json.Unmarshal(line, &o); err != nil {
+			return Order{}, fmt.Errorf("orders: line %d: %w", r.line, err)
+		}
+		return o, nil
+	}
+}
+
+// expectDelim reads the next token and checks that it is want.
+func (r *Reader) expectDelim(want json.Delim) error {
+	t, err := r.dec.Token()
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != want {
+		return fmt.Errorf("orders: expected %q, got %v", want, t)
+	}
+	return nil
+}
+
+// Writer writes Orders one at a time, either as a single JSON array or as
+// newline delimited JSON. Close must be called to finish the document.
+type Writer struct {
+	w      *bufio.Writer
+	enc    *json.Encoder
+	array  bool
+	count  int
+	closed bool
+}
+
+// NewWriter returns a Writer that produces a JSON array of orders.
+func NewWriter(w io.Writer) *Writer {
+	bw := bufio.NewWriter(w)
+	return &Writer{w: bw, enc: json.NewEncoder(bw), array: true}
+}
+
+// NewNDJSONWriter returns a Writer that produces one order per line.
+func NewNDJSONWriter(w io.Writer) *Writer {
+	bw := bufio.NewWriter(w)
+	return &Writer{w: bw, enc: json.NewEncoder(bw)}
+}
+
+// errClosed is returned when writing to a Writer after Close.
+var errClosed = errors.New("orders: write to closed Writer")
+
+// Write encodes o into the stream.
+func (w *Writer) Write(o Order) error {
+	if w.closed {
+		return errClosed
+	}
+	if w.array {
+		sep := byte(',')
+		if w.count == 0 {
+			sep = '['
+		}
+		if err := w.w.WriteByte(sep); err != nil {
+			return err
+		}
+	}
+	// Encode follows every value with a newline, which gives NDJSON
+	// its line breaks and is harmless whitespace inside an array.
+	if err := w.enc.Encode(o); err != nil {
+		return err
+	}
+	w.count++
+	return nil
+}
+
+// Close finishes the document and flushes it to the underlying writer.
+// It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.array {
+		if w.count == 0 {
+			w.w.WriteByte('[')
+		}
+		w.w.WriteString("]\n")
+	}
+	return w.w.Flush()
+}
@@ -0,0 +1,265 @@
+package orders
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// orderFeed is an io.Reader that produces a JSON array of n orders on the
+// fly, so the input itself takes no memory however long it is.
+type orderFeed struct {
+	n, i int
+	buf  []byte
+	done bool
+}
+
+func (f *orderFeed) Read(p []byte) (int, error) {
+	for len(f.buf) == 0 {
+		switch {
+		case f.done:
+			return 0, io.EOF
+		case f.i == f.n:
+			f.buf = append(f.buf, ']')
+			f.done = true
+		default:
+			sep := byte(',')
+			if f.i == 0 {
+				sep = '['
+			}
+			f.buf = append(f.buf, sep)
+			f.buf = fmt.Appendf(f.buf,
+				`{"id":"%d","date_ordered":"2020-05-01T13:01:02Z","customer_id":"c%d","items":[{"id":"i1","name":"widget"},{"id":"i2","name":"gadget"}]}`,
+				f.i, f.i%100)
+			f.i++
+		}
+	}
+	n := copy(p, f.buf)
+	f.buf = f.buf[:copy(f.buf, f.buf[n:])]
+	return n, nil
+}
+
+func TestReaderFeed(t *testing.T) {
+	r := NewReader(&orderFeed{n: 3})
+	for i := 0; ; i++ {
+		o, err := r.Next()
+		if err == io.EOF {
+			if i != 3 {
+				t.Fatalf("read %d orders, want 3", i)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprint(i); o.ID != want || len(o.Items) != 2 {
+			t.Fatalf("order %d = %+v", i, o)
+		}
+	}
+}
+
+// BenchmarkReaderMemory reads feeds of growing length and reports the
+// largest live heap seen while reading, measured after a collection at ten
+// points of every feed. It stays the same whatever the number of orders,
+// which is what Reader promises; B/op grows with the length only because
+// every Order is a new allocation that soon becomes garbage.
+func BenchmarkReaderMemory(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 100_000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.ReportAllocs()
+			var peak uint64
+			var ms runtime.MemStats
+			for i := 0; i < b.N; i++ {
+				r := NewReader(&orderFeed{n: n})
+				for j := 0; ; j++ {
+					if _, err := r.Next(); err == io.EOF {
+						break
+					} else if err != nil {
+						b.Fatal(err)
+					}
+					if j%(n/10) == 0 {
+						runtime.GC()
+						runtime.ReadMemStats(&ms)
+						peak = max(peak, ms.HeapAlloc)
+					}
+				}
+			}
+			b.ReportMetric(float64(peak)/1024, "live-heap-KiB")
+		})
+	}
+}
+
+func testOrders() []Order {
+	date := time.Date(2020, 5, 1, 13, 1, 2, 0, time.UTC)
+	return []Order{
+		{ID: "1", DateOrdered: FlexibleTime{date}, CustomerID: "c1", Items: []Item{{ID: "i1", Name: "widget"}}},
+		{ID: "2", DateOrdered: FlexibleTime{date.Add(time.Hour)}, CustomerID: "c2", Items: []Item{}},
+		{ID: "3", DateOrdered: FlexibleTime{date.Add(48 * time.Hour)}, CustomerID: "c1", Items: []Item{{ID: "i2", Name: "gadget"}, {ID: "i3", Name: "gizmo"}}},
+	}
+}
+
+// readAll reads r to the end, stopping at the first error.
+func readAll(r *Reader) ([]Order, error) {
+	var list []Order
+	for {
+		o, err := r.Next()
+		if err == io.EOF {
+			return list, nil
+		}
+		if err != nil {
+			return list, err
+		}
+		list = append(list, o)
+	}
+}
+
+func sameOrders(a, b []Order) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID || a[i].CustomerID != b[i].CustomerID ||
+			!a[i].DateOrdered.Equal(b[i].DateOrdered.Time) || !reflect.DeepEqual(a[i].Items, b[i].Items) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWriter(t *testing.T) {
+	o := Order{ID: "1", DateOrdered: FlexibleTime{time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)}, CustomerID: "c", Items: []Item{}}
+	line := `{"id":"1","date_ordered":"2020-05-01T00:00:00Z","customer_id":"c","items":[]}` + "\n"
+	tests := []struct {
+		name   string
+		writer func(io.Writer) *Writer
+		orders []Order
+		want   string
+	}{
+		{"empty array", NewWriter, nil, "[]\n"},
+		{"array", NewWriter, []Order{o, o}, "[" + line + "," + line + "]\n"},
+		{"empty NDJSON", NewNDJSONWriter, nil, ""},
+		{"NDJSON", NewNDJSONWriter, []Order{o, o}, line + line},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w := tt.writer(&buf)
+		for _, o := range tt.orders {
+			if err := w.Write(o); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s: wrote %q, want %q", tt.name, buf.String(), tt.want)
+		}
+		if err := w.Close(); err != nil || buf.String() != tt.want {
+			t.Errorf("%s: second Close = %v and wrote %q", tt.name, err, buf.String())
+		}
+		if err := w.Write(o); err != errClosed {
+			t.Errorf("%s: Write after Close = %v, want errClosed", tt.name, err)
+		}
+	}
+}
+
+func TestWriterError(t *testing.T) {
+	w := NewNDJSONWriter(failingWriter{})
+	for i := 0; i < 100; i++ { // more than the buffer holds
+		if err := w.Write(testOrders()[0]); err != nil {
+			return
+		}
+	}
+	if err := w.Close(); err == nil {
+		t.Error("writing to a failing writer reported no error")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestRoundTrip(t *testing.T) {
+	for _, ndjson := range []bool{false, true} {
+		newWriter, newReader := NewWriter, NewReader
+		if ndjson {
+			newWriter, newReader = NewNDJSONWriter, NewNDJSONReader
+		}
+		var buf bytes.Buffer
+		w := newWriter(&buf)
+		for _, o := range testOrders() {
+			if err := w.Write(o); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		// OneByteReader makes every token and line span many reads.
+		got, err := readAll(newReader(iotest.OneByteReader(&buf)))
+		if err != nil || !sameOrders(got, testOrders()) {
+			t.Errorf("ndjson %t: read back %+v, %v", ndjson, got, err)
+		}
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	in := `{"id":"1","date_ordered":"2020-05-01","customer_id":"c1","items":[]}
+
+{"id":"2","date_ordered":"2020-05-02","customer_id":"c2","items":[]}
+{"id":"3", "date_ordered":
+{"id":"4","date_ordered":"2020-05-04","customer_id":"c4","items":[]} trailing
+{"id":"5","date_ordered":"2020-05-05","customer_id":"c5","items":[]}`
+	r := NewNDJSONReader(strings.NewReader(in))
+	want := []struct {
+		id      string
+		errLine int // line named in the error, if there is one
+	}{{"1", 0}, {"2", 0}, {"", 4}, {"", 5}, {"5", 0}}
+	for _, w := range want {
+		o, err := r.Next()
+		switch {
+		case w.errLine == 0 && (err != nil || o.ID != w.id):
+			t.Fatalf("Next = %q, %v; want order %s", o.ID, err, w.id)
+		case w.errLine != 0 && (err == nil || !strings.Contains(err.Error(), fmt.Sprintf("line %d:", w.errLine))):
+			t.Fatalf("Next = %q, %v; want an error for line %d", o.ID, err, w.errLine)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := r.Next(); err != io.EOF {
+			t.Fatalf("Next after the last line = %v, want io.EOF", err)
+		}
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	tests := []struct {
+		name, in string
+		orders   int // read before the error
+	}{
+		{"empty", "", 0},
+		{"not an array", `{"id":"1"}`, 0},
+		{"truncated", `[{"id":"1"},{"id":`, 1},
+		{"unclosed", `[{"id":"1"}`, 1},
+		{"trailing data", `[{"id":"1"}] {}`, 1},
+		{"wrong type", `[{"id":1}]`, 0},
+	}
+	for _, tt := range tests {
+		got, err := readAll(NewReader(strings.NewReader(tt.in)))
+		if err == nil || len(got) != tt.orders {
+			t.Errorf("%s: read %d orders and %v, want %d and an error", tt.name, len(got), err, tt.orders)
+		}
+	}
+	if got, err := readAll(NewReader(strings.NewReader(" [ ] \n"))); err != nil || len(got) != 0 {
+		t.Errorf("empty array: %v, %v", got, err)
+	}
+	if _, err := readAll(NewReader(iotest.TimeoutReader(strings.NewReader(`[{"id":"1"}]`)))); err != iotest.ErrTimeout {
+		t.Errorf("a failing reader gave %v, want iotest.ErrTimeout", err)
+	}
+}
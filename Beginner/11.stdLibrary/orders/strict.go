@@ -0,0 +1,276 @@
+package orders
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldError is a problem with one value of a JSON document.
+type FieldError struct {
+	Path string // JSON pointer (RFC 6901) to the value, e.g. "/items/1/id"
+	Msg  string
+}
+
+func (e FieldError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	return path + ": " + e.Msg
+}
+
+// ValidationError holds every FieldError found in a document.
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "orders: invalid document: " + strings.Join(msgs, "; ")
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// DecodeStrict reads one JSON value from r into v, which must be a non-nil pointer.
+//
+// Unlike json.Unmarshal, which ignores anything it does not understand,
+// DecodeStrict rejects unknown fields, missing required fields (see
+// field.required), null where the Go type has no nil, values of the
+// wrong type and anything after the value. Rather than stopping at
+// the first problem it checks the whole document and returns a
+// ValidationError listing every problem with its JSON pointer path.
+// v is only modified if the document is valid.
+func DecodeStrict(r io.Reader, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("orders: DecodeStrict needs a non-nil pointer, got %T", v)
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber() // keep numbers exact so integers can be range checked
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+	switch _, err := dec.Token(); err {
+	case io.EOF:
+	case nil:
+		return fmt.Errorf("orders: unexpected data after the document")
+	default:
+		return err
+	}
+
+	var errs ValidationError
+	check(&errs, "", doc, rv.Type().Elem())
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// The document matches the type, so the standard decoder can fill in v.
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// UnmarshalStrict is DecodeStrict for a byte slice.
+func UnmarshalStrict(data []byte, v any) error {
+	return DecodeStrict(bytes.NewReader(data), v)
+}
+
+// check compares the decoded value val with the Go type t and appends
+// every mismatch to errs.
+func check(errs *ValidationError, path string, val any, t reflect.Type) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, FieldError{Path: path, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	if val == nil {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		default:
+			fail("null is not allowed for %s", t)
+		}
+		return
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Types that decode themselves, such as time.Time, are checked by
+	// letting them decode the value.
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		raw, _ := json.Marshal(val)
+		if err := reflect.New(t).Interface().(json.Unmarshaler).UnmarshalJSON(raw); err != nil {
+			fail("%v", err)
+		}
+		return
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		s, ok := val.(string)
+		if !ok {
+			fail("expected string, got %s", jsonKind(val))
+			return
+		}
+		if err := reflect.New(t).Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			fail("%v", err)
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := val.(map[string]any)
+		if !ok {
+			fail("expected object, got %s", jsonKind(val))
+			return
+		}
+		known := map[string]bool{}
+		for _, f := range jsonFields(t) {
+			known[f.name] = true
+			v, present := obj[f.name]
+			if !present {
+				if f.required() {
+					*errs = append(*errs, FieldError{Path: path + "/" + escapePointer(f.name), Msg: "missing required field"})
+				}
+				continue
+			}
+			check(errs, path+"/"+escapePointer(f.name), v, f.typ)
+		}
+		var unknown []string
+		for k := range obj {
+			if !known[k] {
+				unknown = append(unknown, k)
+			}
+		}
+		sort.Strings(unknown)
+		for _, k := range unknown {
+			*errs = append(*errs, FieldError{Path: path + "/" + escapePointer(k), Msg: "unknown field"})
+		}
+
+	case reflect.Map:
+		obj, ok := val.(map[string]any)
+		if !ok {
+			fail("expected object, got %s", jsonKind(val))
+			return
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			check(errs, path+"/"+escapePointer(k), obj[k], t.Elem())
+		}
+
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as a base64 string.
+			s, ok := val.(string)
+			if !ok {
+				fail("expected base64 string, got %s", jsonKind(val))
+			} else if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+				fail("invalid base64: %v", err)
+			}
+			return
+		}
+		arr, ok := val.([]any)
+		if !ok {
+			fail("expected array, got %s", jsonKind(val))
+			return
+		}
+		if t.Kind() == reflect.Array && len(arr) > t.Len() {
+			fail("expected at most %d elements, got %d", t.Len(), len(arr))
+		}
+		for i, v := range arr {
+			check(errs, path+"/"+strconv.Itoa(i), v, t.Elem())
+		}
+
+	case reflect.String:
+		if _, ok := val.(string); !ok {
+			fail("expected string, got %s", jsonKind(val))
+		}
+
+	case reflect.Bool:
+		if _, ok := val.(bool); !ok {
+			fail("expected boolean, got %s", jsonKind(val))
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := val.(json.Number)
+		if !ok {
+			fail("expected integer, got %s", jsonKind(val))
+			return
+		}
+		i, err := strconv.ParseInt(string(n), 10, 64)
+		if err != nil || reflect.Zero(t).OverflowInt(i) {
+			fail("%s is not a valid %s", n, t)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := val.(json.Number)
+		if !ok {
+			fail("expected integer, got %s", jsonKind(val))
+			return
+		}
+		u, err := strconv.ParseUint(string(n), 10, 64)
+		if err != nil || reflect.Zero(t).OverflowUint(u) {
+			fail("%s is not a valid %s", n, t)
+		}
+
+	case reflect.Float32, reflect.Float64:
+		n, ok := val.(json.Number)
+		if !ok {
+			fail("expected number, got %s", jsonKind(val))
+			return
+		}
+		f, err := n.Float64()
+		if err != nil || (t.Kind() == reflect.Float32 && math.Abs(f) > math.MaxFloat32) {
+			fail("%s is not a valid %s", n, t)
+		}
+
+	case reflect.Interface:
+		// Any value fits an interface.
+
+	default:
+		fail("%s can not be decoded from JSON", t)
+	}
+}
+
+// jsonKind names the JSON type of a value produced by a decoder using UseNumber.
+func jsonKind(val any) string {
+	switch val.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", val)
+}
+
+// escapePointer escapes a key for use in a JSON pointer.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
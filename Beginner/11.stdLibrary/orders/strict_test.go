@@ -0,0 +1,106 @@
+package orders
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeStrict(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		errs []string // FieldError paths, or nil for a valid document
+	}{
+		{"valid", `{"name":"Bob","age":30,"sex":"M"}`, nil},
+		{"omitempty field left out", `{"name":"Bob","sex":"M"}`, nil},
+		{"required field left out", `{"age":30,"sex":"M"}`, []string{"/name"}},
+		{"null string", `{"name":null,"age":30,"sex":"M"}`, []string{"/name"}},
+		{"unknown field", `{"name":"Bob","age":30,"sex":"M","job":"x"}`, []string{"/job"}},
+		{"every problem", `{"name":1,"job":"x"}`, []string{"/name", "/sex", "/job"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Person
+			err := DecodeStrict(strings.NewReader(tt.in), &p)
+			if tt.errs == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var ve ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("got %v, want a ValidationError", err)
+			}
+			var paths []string
+			for _, fe := range ve {
+				paths = append(paths, fe.Path)
+			}
+			if strings.Join(paths, " ") != strings.Join(tt.errs, " ") {
+				t.Errorf("errors at %q, want %q", paths, tt.errs)
+			}
+			if p != (Person{}) {
+				t.Errorf("invalid document modified v: %+v", p)
+			}
+		})
+	}
+}
+
+func TestDecodeStrictNullSlice(t *testing.T) {
+	// Marshal writes nil slices as null, so they must be read back.
+	var o Order
+	in := `{"id":"1","date_ordered":"2020-05-01","customer_id":"c","items":null}`
+	if err := DecodeStrict(strings.NewReader(in), &o); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeStrictTrailingData(t *testing.T) {
+	for _, in := range []string{
+		`{"name":"Bob","age":30,"sex":"M"} {}`,
+		`{"name":"Bob","age":30,"sex":"M"}]`,
+		`{"name":"Bob","age":30,"sex":"M"} x`,
+	} {
+		var p Person
+		if err := DecodeStrict(strings.NewReader(in), &p); err == nil {
+			t.Errorf("DecodeStrict(%s) succeeded, want an error", in)
+		}
+	}
+	var p Person
+	if err := DecodeStrict(strings.NewReader("{\"name\":\"Bob\",\"age\":30,\"sex\":\"M\"}\n\t "), &p); err != nil {
+		t.Errorf("trailing whitespace: %v", err)
+	}
+}
+
+// TestMarshalRoundTrip checks that whatever json.Marshal writes for the
+// package's own types, zero values included, UnmarshalStrict accepts.
+func TestMarshalRoundTrip(t *testing.T) {
+	date := FlexibleTime{time.Date(2020, 5, 1, 13, 1, 2, 0, time.UTC)}
+	values := []any{
+		&Person{Name: "Bob", Sex: "M"}, // Age left out by omitempty
+		&Person{Name: "Ann", Age: 41, Sex: "F", Occupation: "not written"},
+		&Person{},
+		&Order{ID: "1", DateOrdered: date, CustomerID: "c1", Items: []Item{{ID: "i1", Name: "widget"}}},
+		&Order{ID: "2", DateOrdered: date}, // nil Items is written as null
+		&Order{},
+	}
+	for _, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := reflect.New(reflect.TypeOf(v).Elem()).Interface()
+		if err := UnmarshalStrict(data, got); err != nil {
+			t.Errorf("UnmarshalStrict(%s) = %v", data, err)
+			continue
+		}
+		again, _ := json.Marshal(got)
+		if string(again) != string(data) {
+			t.Errorf("round trip changed %s to %s", data, again)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Command textstats is a Unicode aware wc. Besides lines, words and
+// bytes it counts runes and graphemes, and can list letter frequencies
+// and the most frequent words.
+//
+// Usage:
+//
+//	textstats [-l] [-w] [-c] [-m] [-g] [-letters] [-top N] [-stop file] [-no-stop] [file ...]
+//
+// With no files it reads standard input. With none of -l, -w, -c, -m and
+// -g it prints lines, words and bytes, like wc.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/learning-go-book/textstats"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("textstats: ")
+
+	lines := flag.Bool("l", false, "print the line count")
+	words := flag.Bool("w", false, "print the word count")
+	bytes := flag.Bool("c", false, "print the byte count")
+	runes := flag.Bool("m", false, "print the rune count")
+	graphemes := flag.Bool("g", false, "print the grapheme count")
+	letters := flag.Bool("letters", false, "print how often each letter appears")
+	top := flag.Int("top", 0, "print the `N` most frequent words")
+	stopFile := flag.String("stop", "", "read stop words from `file` instead of the built-in English list")
+	noStop := flag.Bool("no-stop", false, "do not filter stop words from -top")
+	flag.Parse()
+
+	if !*lines && !*words && !*bytes && !*runes && !*graphemes {
+		*lines, *words, *bytes = true, true, true
+	}
+	columns := func(st textstats.Stats) string {
+		var cols []string
+		add := func(on bool, n int) {
+			if on {
+				cols = append(cols, fmt.Sprintf("%8d", n))
+			}
+		}
+		add(*lines, st.Lines)
+		add(*words, st.Words)
+		add(*runes, st.Runes)
+		add(*graphemes, st.Graphemes)
+		add(*bytes, st.Bytes)
+		return strings.Join(cols, "")
+	}
+
+	stop := map[string]bool{}
+	if !*noStop {
+		list := textstats.DefaultStopWords
+		if *stopFile != "" {
+			f, err := os.Open(*stopFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			list, err = textstats.ReadStopWords(f)
+			f.Close()
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		stop = textstats.StopWordSet(list)
+	}
+
+	total := textstats.NewStats()
+	files := flag.Args()
+	failed := false
+	process := func(name string, r io.Reader) {
+		st, err := textstats.Count(r)
+		if err != nil {
+			log.Printf("%s: %v", name, err)
+			failed = true
+			return
+		}
+		fmt.Printf("%s %s\n", columns(st), name)
+		if st.Invalid > 0 {
+			log.Printf("%s: %d bytes of invalid UTF-8", name, st.Invalid)
+		}
+		total.Add(st)
+	}
+
+	if len(files) == 0 {
+		process("", os.Stdin)
+	}
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			log.Print(err)
+			failed = true
+			continue
+		}
+		process(name, f)
+		f.Close()
+	}
+	if len(files) > 1 {
+		fmt.Printf("%s total\n", columns(total))
+	}
+
+	if *letters {
+		fmt.Println()
+		keys := make([]rune, 0, len(total.Letters))
+		for r := range total.Letters {
+			keys = append(keys, r)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		for _, r := range keys {
+			fmt.Printf("%c %d\n", r, total.Letters[r])
+		}
+	}
+	if *top > 0 {
+		fmt.Println()
+		for _, wc := range textstats.TopWords(total.WordSet, *top, stop) {
+			fmt.Printf("%8d %s\n", wc.Count, wc.Word)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
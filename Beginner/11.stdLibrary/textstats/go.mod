@@ -0,0 +1,7 @@
+module github.com/learning-go-book/textstats
+
+go 1.21
+
+require github.com/learning-go-book/textx v0.0.0
+
+replace github.com/learning-go-book/textx => ../../2.CompositeTypes/2.stringRunes/textx
@@ -0,0 +1,186 @@
+// Package textstats counts the lines, words, bytes, runes and graphemes
+// of a text, like a Unicode aware wc, along with how often each letter
+// and word appears. The textstats command in cmd/textstats prints them.
+package textstats
+
+import (
+	"io"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/learning-go-book/textx"
+)
+
+// Stats holds everything counted in a text.
+type Stats struct {
+	Bytes     int
+	Runes     int
+	Graphemes int // user-perceived characters, see textx.GraphemeBreaker
+	Lines     int // number of '\n', like wc -l
+	Words     int
+	Invalid   int // bytes that are not valid UTF-8
+
+	Letters map[rune]int   // letters, case folded
+	WordSet map[string]int // words, case folded
+}
+
+// counter works like countLetters in ../notes.go, but decodes UTF-8.
+// A multibyte rune can be split across two Read calls, so the bytes of an
+// incomplete rune at the end of one buffer are carried over to the next.
+// Graphemes and words can span buffers too; the state needed for them is
+// kept between calls as well.
+type counter struct {
+	st Stats
+
+	carry     [utf8.UTFMax]byte // start of a rune cut off by the end of the buffer
+	nCarry    int
+	graphemes textx.GraphemeBreaker
+	word      []rune
+}
+
+func newCounter() *counter {
+	return &counter{st: NewStats()}
+}
+
+// Count reads r until io.EOF and returns the statistics.
+func Count(r io.Reader) (Stats, error) {
+	c := newCounter()
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		c.write(buf[:n])
+		if err == io.EOF {
+			return c.finish(), nil
+		}
+		if err != nil {
+			return Stats{}, err
+		}
+	}
+}
+
+// write counts p.
+func (c *counter) write(p []byte) {
+	c.st.Bytes += len(p)
+
+	// Complete the rune left over from the last call first.
+	for c.nCarry > 0 && len(p) > 0 {
+		c.carry[c.nCarry] = p[0]
+		c.nCarry++
+		p = p[1:]
+		if utf8.FullRune(c.carry[:c.nCarry]) {
+			r, size := utf8.DecodeRune(c.carry[:c.nCarry])
+			c.rune(r, size)
+			// An invalid sequence may use fewer bytes than we collected;
+			// decode the rest of them on their own.
+			rest := append([]byte(nil), c.carry[size:c.nCarry]...)
+			c.nCarry = 0
+			c.decode(rest, false)
+		}
+	}
+	c.decode(p, true)
+}
+
+// decode counts the runes in p. When keepTail is true an incomplete rune
+// at the end of p is saved for the next call instead of being counted
+// as invalid.
+func (c *counter) decode(p []byte, keepTail bool) {
+	for len(p) > 0 {
+		if keepTail && !utf8.FullRune(p) {
+			c.nCarry = copy(c.carry[:], p)
+			return
+		}
+		r, size := utf8.DecodeRune(p)
+		c.rune(r, size)
+		p = p[size:]
+	}
+}
+
+// rune counts one decoded rune of size bytes.
+func (c *counter) rune(r rune, size int) {
+	if r == utf8.RuneError && size == 1 {
+		c.st.Invalid++
+	}
+	c.st.Runes++
+	if r == '\n' {
+		c.st.Lines++
+	}
+	if c.graphemes.Next(r) {
+		c.st.Graphemes++
+	}
+
+	if unicode.IsLetter(r) {
+		c.st.Letters[fold(r)]++
+	}
+	switch {
+	case inWord(r):
+		c.word = append(c.word, fold(r))
+	case isApostrophe(r) && len(c.word) > 0:
+		// Keep "don't" together; a trailing apostrophe is trimmed in endWord.
+		c.word = append(c.word, '\'')
+	default:
+		c.endWord()
+	}
+}
+
+// endWord counts the word collected so far, if any.
+func (c *counter) endWord() {
+	for len(c.word) > 0 && c.word[len(c.word)-1] == '\'' {
+		c.word = c.word[:len(c.word)-1]
+	}
+	if len(c.word) > 0 {
+		c.st.Words++
+		c.st.WordSet[string(c.word)]++
+	}
+	c.word = c.word[:0]
+}
+
+// finish counts what is still pending and returns the result.
+func (c *counter) finish() Stats {
+	if c.nCarry > 0 {
+		// The input ended in the middle of a rune.
+		c.decode(c.carry[:c.nCarry], false)
+		c.nCarry = 0
+	}
+	c.endWord()
+	return c.st
+}
+
+// inWord reports whether r is part of a word: letters, digits, and the
+// combining marks that decorate them.
+func inWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.M, r)
+}
+
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
+
+// fold maps r to a canonical case so that 'A' and 'a', or 'Σ', 'σ' and
+// the final 'ς', are counted together. Going through the uppercase form
+// first is what makes 'ς' and the Kelvin sign 'K' fold like their
+// ordinary letters.
+func fold(r rune) rune {
+	return unicode.ToLower(unicode.ToUpper(r))
+}
+
+// NewStats returns empty Stats, ready to Add to.
+func NewStats() Stats {
+	return Stats{Letters: map[rune]int{}, WordSet: map[string]int{}}
+}
+
+// Add adds the counts of o to st, which must have been made by NewStats
+// or returned by Count.
+func (st *Stats) Add(o Stats) {
+	st.Bytes += o.Bytes
+	st.Runes += o.Runes
+	st.Graphemes += o.Graphemes
+	st.Lines += o.Lines
+	st.Words += o.Words
+	st.Invalid += o.Invalid
+	for r, n := range o.Letters {
+		st.Letters[r] += n
+	}
+	for w, n := range o.WordSet {
+		st.WordSet[w] += n
+	}
+}
@@ -0,0 +1,69 @@
+package textstats
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		in                                  string
+		lines, words, runes, graphemes, inv int
+	}{
+		{"", 0, 0, 0, 0, 0},
+		{"hello world\n", 1, 2, 12, 12, 0},
+		{"don't stop\r\n", 1, 2, 12, 11, 0},
+		{"été", 0, 1, 5, 3, 0},
+		{"\U0001f1f3\U0001f1f5\U0001f1ea", 0, 0, 3, 2, 0},
+		{"\U0001f469‍\U0001f4bb ok", 0, 1, 6, 4, 0},
+		{"a\xffb", 0, 2, 3, 3, 1},
+	}
+	for _, tt := range tests {
+		// One byte at a time splits every multibyte rune across reads.
+		readers := map[string]io.Reader{
+			"whole":    strings.NewReader(tt.in),
+			"bytewise": iotest.OneByteReader(strings.NewReader(tt.in)),
+		}
+		for name, r := range readers {
+			st, err := Count(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := [...]int{st.Lines, st.Words, st.Runes, st.Graphemes, st.Invalid, st.Bytes}
+			want := [...]int{tt.lines, tt.words, tt.runes, tt.graphemes, tt.inv, len(tt.in)}
+			if got != want {
+				t.Errorf("%s Count(%q) = %v, want %v (lines, words, runes, graphemes, invalid, bytes)", name, tt.in, got, want)
+			}
+		}
+	}
+}
+
+func TestTopWords(t *testing.T) {
+	st, err := Count(strings.NewReader("The cat and THE dog and the Cat. Σοφία σοφίας"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := TopWords(st.WordSet, 3, StopWordSet(DefaultStopWords))
+	want := []WordCount{{"cat", 2}, {"dog", 1}, {"σοφία", 1}}
+	if len(got) != len(want) {
+		t.Fatalf("TopWords = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("TopWords = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestReadStopWords(t *testing.T) {
+	words, err := ReadStopWords(strings.NewReader("# comment\nfoo bar\n\n  baz # not a comment\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(words, " "); got != "foo bar baz # not a comment" {
+		t.Errorf("ReadStopWords = %q", got)
+	}
+}
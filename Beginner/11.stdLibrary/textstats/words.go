@@ -0,0 +1,70 @@
+package textstats
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DefaultStopWords are common English words left out of the top words,
+// since they would otherwise fill the whole list.
+var DefaultStopWords = strings.Fields(`
+	a about after all also an and any are as at be because been but by can
+	could did do does for from had has have he her him his how i if in into
+	is it its just me more my no not of on one or our out over she so some
+	than that the their them then there these they this to up us was we were
+	what when which who will with would you your
+`)
+
+// WordCount is a word and how often it appeared.
+type WordCount struct {
+	Word  string
+	Count int
+}
+
+// TopWords returns the n most frequent words that are not in stop, most
+// frequent first; ties are ordered alphabetically. n <= 0 returns all.
+func TopWords(words map[string]int, n int, stop map[string]bool) []WordCount {
+	list := make([]WordCount, 0, len(words))
+	for w, c := range words {
+		if !stop[w] {
+			list = append(list, WordCount{w, c})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Word < list[j].Word
+	})
+	if n > 0 && len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// StopWordSet builds the set of stop words for TopWords, folded the same
+// way the counted words are.
+func StopWordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[strings.Map(fold, w)] = true
+	}
+	return set
+}
+
+// ReadStopWords reads whitespace separated stop words from r.
+// Lines starting with '#' are comments.
+func ReadStopWords(r io.Reader) ([]string, error) {
+	var words []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, strings.Fields(line)...)
+	}
+	return words, sc.Err()
+}
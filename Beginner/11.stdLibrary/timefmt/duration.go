@@ -0,0 +1,153 @@
+package timefmt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// durationUnits are the units FormatDuration writes, largest first.
+var durationUnits = []struct {
+	name string
+	size time.Duration
+}{
+	{"day", day},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+	{"second", time.Second},
+	{"millisecond", time.Millisecond},
+}
+
+// FormatDuration writes d in words, for example
+//
+//	d := 2*time.Hour + 30*time.Minute
+//	FormatDuration(d) // "2 hours 30 minutes"
+//
+// Units that are zero are left out, and d is rounded to the millisecond.
+// A day is always 24 hours.
+func FormatDuration(d time.Duration) string {
+	return FormatDurationUnits(d, len(durationUnits))
+}
+
+// FormatDurationUnits is like FormatDuration but writes at most n units,
+// rounding the last one: FormatDurationUnits(26*time.Hour+40*time.Minute, 1)
+// is "1 day".
+func FormatDurationUnits(d time.Duration, n int) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	// Find the largest unit that is not zero, then round d to the
+	// smallest unit that will be written.
+	first := len(durationUnits) - 1
+	for i, u := range durationUnits {
+		if d >= u.size {
+			first = i
+			break
+		}
+	}
+	last := min(first+max(n, 1)-1, len(durationUnits)-1)
+	d = d.Round(durationUnits[last].size)
+
+	var parts []string
+	for _, u := range durationUnits[:last+1] {
+		v := d / u.size
+		d -= v * u.size
+		if v == 0 {
+			continue
+		}
+		name := u.name
+		if v != 1 {
+			name += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", v, name))
+	}
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+// durationWords maps the unit words ParseDuration accepts to their size.
+var durationWords = map[string]time.Duration{
+	"ms": time.Millisecond, "millisecond": time.Millisecond, "milliseconds": time.Millisecond,
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": day, "day": day, "days": day,
+	"w": week, "week": week, "weeks": week,
+}
+
+// ParseDuration reads a duration written as time.ParseDuration expects
+// ("2h30m"), in words ("2 hours 30 minutes", "1 day and 3 hours"), or a
+// mix of both. Days and weeks are accepted and are always 24 hours and
+// 7 days long.
+func ParseDuration(s string) (time.Duration, error) {
+	days, rest, err := parseDuration(s)
+	return time.Duration(days)*day + rest, err
+}
+
+// parseDuration is ParseDuration keeping whole days, given in days or
+// weeks, apart from the rest, so that Parser can count them as calendar
+// days.
+func parseDuration(s string) (days int, rest time.Duration, err error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		return 0, d, nil
+	}
+
+	fields := strings.Fields(strings.ReplaceAll(s, ",", " "))
+	if len(fields) == 0 {
+		return 0, 0, fmt.Errorf("empty duration")
+	}
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if f == "and" {
+			continue
+		}
+		if f == "a" || f == "an" {
+			f = "1"
+		}
+		// The number and the unit may be written together ("3d") or apart ("3 days").
+		num, unit := splitNumber(f)
+		if unit == "" && i+1 < len(fields) {
+			i++
+			unit = fields[i]
+		}
+		n, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid duration %q: %q is not a number", s, num)
+		}
+		size, ok := durationWords[unit]
+		if !ok {
+			return 0, 0, fmt.Errorf("invalid duration %q: unknown unit %q", s, unit)
+		}
+		if size >= day {
+			nd := n * float64(size/day)
+			whole := math.Trunc(nd)
+			days += int(whole)
+			rest += time.Duration((nd - whole) * float64(day))
+			continue
+		}
+		rest += time.Duration(n * float64(size))
+	}
+	return days, rest, nil
+}
+
+// splitNumber splits "30min" into "30" and "min".
+func splitNumber(s string) (num, unit string) {
+	i := 0
+	for i < len(s) && (s[i] == '.' || s[i] == '-' || (s[i] >= '0' && s[i] <= '9')) {
+		i++
+	}
+	return s[:i], s[i:]
+}
@@ -0,0 +1,91 @@
+package timefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0 seconds"},
+		{time.Second, "1 second"},
+		{2*time.Hour + 30*time.Minute, "2 hours 30 minutes"},
+		{26*time.Hour + 1500*time.Millisecond, "1 day 2 hours 1 second 500 milliseconds"},
+		{-90 * time.Minute, "-1 hour 30 minutes"},
+		{400 * time.Microsecond, "0 seconds"},
+		{500 * time.Microsecond, "1 millisecond"}, // rounded, not cut off
+		{8 * 24 * time.Hour, "8 days"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDurationUnits(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		n    int
+		want string
+	}{
+		{26*time.Hour + 40*time.Minute, 1, "1 day"},
+		{26*time.Hour + 40*time.Minute, 2, "1 day 3 hours"},
+		{36 * time.Hour, 1, "2 days"}, // rounded half up
+		{59*time.Minute + 40*time.Second, 1, "1 hour"},
+		{90 * time.Second, 0, "2 minutes"}, // n below 1 counts as 1
+		{-150 * time.Minute, 1, "-3 hours"},
+		{time.Hour + time.Second, 2, "1 hour"}, // zero units are left out
+	}
+	for _, tt := range tests {
+		if got := FormatDurationUnits(tt.d, tt.n); got != tt.want {
+			t.Errorf("FormatDurationUnits(%v, %d) = %q, want %q", tt.d, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"2h30m", 150 * time.Minute},
+		{"2 hours 30 minutes", 150 * time.Minute},
+		{"1 day and 3 hours", 27 * time.Hour},
+		{"1 week, 2 days", 9 * 24 * time.Hour},
+		{"3d", 72 * time.Hour},
+		{"1.5 days", 36 * time.Hour},
+		{"an hour", time.Hour},
+		{"a minute 30s", 90 * time.Second},
+		{"  45 secs ", 45 * time.Second},
+		{"250ms", 250 * time.Millisecond},
+		{"2h 15 min", 135 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "   ", "soon", "3 fortnights", "x hours", "5"} {
+		if d, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) = %v, want an error", in, d)
+		}
+	}
+}
+
+func TestDurationRoundTrip(t *testing.T) {
+	for _, d := range []time.Duration{
+		time.Millisecond, 59 * time.Second, 2*time.Hour + 30*time.Minute,
+		26*time.Hour + 1500*time.Millisecond, 400 * 24 * time.Hour,
+	} {
+		s := FormatDuration(d)
+		got, err := ParseDuration(s)
+		if err != nil || got != d {
+			t.Errorf("%v formats as %q and parses back as %v, %v", d, s, got, err)
+		}
+	}
+}
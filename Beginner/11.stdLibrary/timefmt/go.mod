@@ -0,0 +1,3 @@
+module github.com/learning-go-book/timefmt
+
+go 1.21
@@ -0,0 +1,181 @@
+package timefmt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Clock tells the current time. Tests can provide a fixed one so that
+// relative expressions give predictable results.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time { return f() }
+
+// SystemClock is the Clock backed by time.Now.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// Parser parses relative time expressions.
+type Parser struct {
+	Clock    Clock          // defaults to SystemClock
+	Location *time.Location // defaults to the Location of Clock.Now()
+}
+
+// ParseRelative parses s relative to the current time using SystemClock.
+func ParseRelative(s string) (time.Time, error) {
+	return Parser{}.Parse(s)
+}
+
+// timeOfDayLayouts are accepted after a day word, as in "tomorrow 3pm".
+var timeOfDayLayouts = []string{"15:04", "15:04:05", "3pm", "3:04pm", "3PM", "3:04PM"}
+
+// absoluteLayouts are tried when s is not a relative expression.
+var absoluteLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// Parse understands:
+//
+//	now, today, tomorrow, yesterday   optionally followed by a time: "yesterday 15:00"
+//	in 2h30m, in 2 hours 30 minutes   a duration from now
+//	3 days ago, 90m ago               a duration before now
+//	monday, next monday, last friday  optionally followed by a time
+//	2020-05-01 13:01                  absolute times, see absoluteLayouts
+//
+// Days are calendar days in the parser's Location, so "tomorrow 09:00"
+// and "3 days ago" keep the time of day across daylight saving changes.
+// Hours and smaller units are exact, so "72h ago" may not.
+func (p Parser) Parse(s string) (time.Time, error) {
+	clock := p.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	now := clock.Now()
+	loc := p.Location
+	if loc == nil {
+		loc = now.Location()
+	}
+	now = now.In(loc)
+
+	// Only the keywords are case insensitive. Times of day and absolute
+	// times keep their case, since layouts such as RFC 3339 ("T", "Z")
+	// and "3PM" depend on it.
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return time.Time{}, fmt.Errorf("timefmt: empty time expression")
+	}
+	s = strings.Join(fields, " ")
+	word := func(i int) string {
+		if i < len(fields) {
+			return strings.ToLower(fields[i])
+		}
+		return ""
+	}
+	from := func(i int) string {
+		if i < len(fields) {
+			return strings.Join(fields[i:], " ")
+		}
+		return ""
+	}
+
+	if word(0) == "in" {
+		days, d, err := parseDuration(strings.ToLower(from(1)))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("timefmt: %q: %w", s, err)
+		}
+		return now.AddDate(0, 0, days).Add(d), nil
+	}
+	if word(len(fields)-1) == "ago" {
+		days, d, err := parseDuration(strings.ToLower(strings.Join(fields[:len(fields)-1], " ")))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("timefmt: %q: %w", s, err)
+		}
+		return now.AddDate(0, 0, -days).Add(-d), nil
+	}
+
+	switch day, clockTime := word(0), from(1); day {
+	case "now":
+		if clockTime != "" {
+			break
+		}
+		return now, nil
+	case "today":
+		return atTime(now, clockTime, s)
+	case "tomorrow":
+		return atTime(now.AddDate(0, 0, 1), clockTime, s)
+	case "yesterday":
+		return atTime(now.AddDate(0, 0, -1), clockTime, s)
+	case "next", "last", "this":
+		if wd, ok := weekday(word(1)); ok {
+			return atTime(shiftToWeekday(now, wd, day), from(2), s)
+		}
+	default:
+		if wd, ok := weekday(day); ok {
+			return atTime(shiftToWeekday(now, wd, "next"), clockTime, s)
+		}
+	}
+
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("timefmt: cannot understand the time expression %q", s)
+}
+
+// atTime returns midnight of day's date, or the given time of day on it.
+func atTime(day time.Time, clockTime, expr string) (time.Time, error) {
+	y, m, d := day.Date()
+	if clockTime == "" {
+		return time.Date(y, m, d, 0, 0, 0, 0, day.Location()), nil
+	}
+	for _, layout := range timeOfDayLayouts {
+		if t, err := time.Parse(layout, clockTime); err == nil {
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, day.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("timefmt: %q: cannot read %q as a time of day", expr, clockTime)
+}
+
+// weekday parses a lower case weekday name or its three letter abbreviation.
+func weekday(name string) (time.Weekday, bool) {
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		full := strings.ToLower(wd.String())
+		if name == full || name == full[:3] {
+			return wd, true
+		}
+	}
+	return 0, false
+}
+
+// shiftToWeekday moves now to the given weekday. "next" picks the first one
+// after today, "last" the last one before today, and "this" the one in
+// the current week (Monday to Sunday).
+func shiftToWeekday(now time.Time, wd time.Weekday, which string) time.Time {
+	diff := int(wd - now.Weekday())
+	switch which {
+	case "next":
+		if diff <= 0 {
+			diff += 7
+		}
+	case "last":
+		if diff >= 0 {
+			diff -= 7
+		}
+	case "this":
+		// Weeks start on Monday, so Sunday counts as day 7.
+		diff = isoWeekday(wd) - isoWeekday(now.Weekday())
+	}
+	return now.AddDate(0, 0, diff)
+}
+
+// isoWeekday numbers the days Monday=1 ... Sunday=7.
+func isoWeekday(wd time.Weekday) int {
+	if wd == time.Sunday {
+		return 7
+	}
+	return int(wd)
+}
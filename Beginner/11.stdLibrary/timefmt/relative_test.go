@@ -0,0 +1,86 @@
+package timefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Wednesday 2020-05-06 10:30 UTC.
+	now := time.Date(2020, 5, 6, 10, 30, 0, 0, time.UTC)
+	p := Parser{Clock: ClockFunc(func() time.Time { return now })}
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"now", now},
+		{"NOW", now},
+		{"in 2h30m", now.Add(150 * time.Minute)},
+		{"In 2 Hours", now.Add(2 * time.Hour)},
+		{"3 days ago", now.AddDate(0, 0, -3)},
+		{"90m AGO", now.Add(-90 * time.Minute)},
+		{"today", time.Date(2020, 5, 6, 0, 0, 0, 0, time.UTC)},
+		{"Tomorrow 3PM", time.Date(2020, 5, 7, 15, 0, 0, 0, time.UTC)},
+		{"tomorrow 3pm", time.Date(2020, 5, 7, 15, 0, 0, 0, time.UTC)},
+		{"yesterday  15:04", time.Date(2020, 5, 5, 15, 4, 0, 0, time.UTC)},
+		{"Next Monday 9:30AM", time.Date(2020, 5, 11, 9, 30, 0, 0, time.UTC)},
+		{"last fri", time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"this sunday", time.Date(2020, 5, 10, 0, 0, 0, 0, time.UTC)},
+		{"2020-05-01T13:01:02Z", time.Date(2020, 5, 1, 13, 1, 2, 0, time.UTC)},
+		{"2020-05-01T13:01:02+02:00", time.Date(2020, 5, 1, 11, 1, 2, 0, time.UTC)},
+		{"2020-05-01 13:01", time.Date(2020, 5, 1, 13, 1, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := p.Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	p := Parser{Clock: ClockFunc(func() time.Time { return time.Date(2020, 5, 6, 0, 0, 0, 0, time.UTC) })}
+	for _, in := range []string{"", "   ", "now 3pm", "in forever", "tomorrow noonish", "2020-13-01"} {
+		if got, err := p.Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestParseAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	// Clocks went forward an hour early on Sunday 2020-03-08, so that
+	// day had 23 hours.
+	now := time.Date(2020, 3, 9, 10, 0, 0, 0, ny)
+	p := Parser{Clock: ClockFunc(func() time.Time { return now })}
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"3 days ago", time.Date(2020, 3, 6, 10, 0, 0, 0, ny)},
+		{"1 week ago", time.Date(2020, 3, 2, 10, 0, 0, 0, ny)},
+		{"2 days 1 hour ago", time.Date(2020, 3, 7, 9, 0, 0, 0, ny)},
+		{"72h ago", time.Date(2020, 3, 6, 9, 0, 0, 0, ny)}, // hours are exact
+		{"yesterday 10:00", time.Date(2020, 3, 8, 10, 0, 0, 0, ny)},
+		{"last friday 10:00", time.Date(2020, 3, 6, 10, 0, 0, 0, ny)},
+	}
+	for _, tt := range tests {
+		got, err := p.Parse(tt.in)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	before := time.Date(2020, 3, 6, 10, 0, 0, 0, ny)
+	p.Clock = ClockFunc(func() time.Time { return before })
+	if got, err := p.Parse("in 3 days"); err != nil || !got.Equal(now) {
+		t.Errorf("Parse(in 3 days) = %v, %v; want %v", got, err, now)
+	}
+}
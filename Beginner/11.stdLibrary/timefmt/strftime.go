@@ -0,0 +1,120 @@
+// Package timefmt builds on the time examples in ../2.go: it translates
+// strftime patterns into Go layouts, parses relative expressions such as
+// "yesterday 15:00" and writes durations the way people say them.
+package timefmt
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// strftimeVerbs maps strftime conversions to Go reference layout elements.
+var strftimeVerbs = map[byte]string{
+	'Y': "2006",    // year
+	'y': "06",      // two digit year
+	'm': "01",      // month 01-12
+	'b': "Jan",     // abbreviated month name
+	'h': "Jan",     // same as %b
+	'B': "January", // full month name
+	'd': "02",      // day of the month 01-31
+	'e': "_2",      // day of the month, space padded
+	'j': "002",     // day of the year 001-366
+	'a': "Mon",     // abbreviated weekday name
+	'A': "Monday",  // full weekday name
+	'H': "15",      // hour 00-23
+	'I': "03",      // hour 01-12
+	'l': "3",       // hour 1-12, not padded
+	'M': "04",      // minute
+	'S': "05",      // second
+	'f': "000000",  // microseconds, must follow a '.' or ','
+	'p': "PM",      // AM or PM
+	'z': "-0700",   // numeric zone offset
+	'Z': "MST",     // zone abbreviation
+	'F': "2006-01-02",
+	'T': "15:04:05",
+	'D': "01/02/06",
+	'R': "15:04",
+	'n': "\n",
+	't': "\t",
+	'%': "%",
+}
+
+// Layout translates a strftime pattern such as "%Y-%m-%d %H:%M" into the
+// equivalent Go layout ("2006-01-02 15:04").
+//
+// Go layouts have no escape character, so literal text that Go would read
+// as part of the reference time (digits, or words like "Jan" or "PM")
+// can not be expressed and is reported as an error.
+func Layout(pattern string) (string, error) {
+	var b strings.Builder
+	lit := 0 // start of the current run of literal text
+	flush := func(end int) error {
+		if s := pattern[lit:end]; s != "" {
+			if elem := layoutElement(s); elem != "" {
+				return fmt.Errorf("timefmt: literal %q in pattern %q would be read as the layout element %q", s, pattern, elem)
+			}
+			b.WriteString(s)
+		}
+		return nil
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			continue
+		}
+		if err := flush(i); err != nil {
+			return "", err
+		}
+		if i+1 == len(pattern) {
+			return "", fmt.Errorf("timefmt: pattern %q ends with a lone %%", pattern)
+		}
+		verb := pattern[i+1]
+		elem, ok := strftimeVerbs[verb]
+		if !ok {
+			return "", fmt.Errorf("timefmt: unsupported conversion %%%c in pattern %q", verb, pattern)
+		}
+		if verb == 'f' && (i == 0 || (pattern[i-1] != '.' && pattern[i-1] != ',')) {
+			return "", fmt.Errorf("timefmt: %%f must follow a '.' or ',' in pattern %q", pattern)
+		}
+		b.WriteString(elem)
+		i++
+		lit = i + 1
+	}
+	if err := flush(len(pattern)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// literalElements are the parts of a Go layout that could sneak in
+// through literal text of a strftime pattern.
+var literalElements = []string{"Jan", "Mon", "MST", "PM", "pm", "Z07", "-07", "0", "1", "2", "3", "4", "5", "6", "7", "_2"}
+
+// layoutElement returns the first Go layout element found in s, or "".
+func layoutElement(s string) string {
+	for _, e := range literalElements {
+		if strings.Contains(s, e) {
+			return e
+		}
+	}
+	return ""
+}
+
+// Format formats t according to a strftime pattern.
+func Format(t time.Time, pattern string) (string, error) {
+	layout, err := Layout(pattern)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(layout), nil
+}
+
+// Parse parses value according to a strftime pattern.
+func Parse(pattern, value string) (time.Time, error) {
+	layout, err := Layout(pattern)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(layout, value)
+}
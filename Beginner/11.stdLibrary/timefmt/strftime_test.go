@@ -0,0 +1,119 @@
+package timefmt
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLayout(t *testing.T) {
+	tests := []struct{ pattern, want string }{
+		{"%Y-%m-%d %H:%M", "2006-01-02 15:04"},
+		{"%F %T", "2006-01-02 15:04:05"},
+		{"%a %d %b %y, %I:%M %p", "Mon 02 Jan 06, 03:04 PM"},
+		{"%A %e %B %j %h", "Monday _2 January 002 Jan"},
+		{"%D %R %z %Z", "01/02/06 15:04 -0700 MST"},
+		{"%H:%M:%S.%f", "15:04:05.000000"},
+		{"%S,%f", "05,000000"},
+		{"%l o'clock", "3 o'clock"},
+		{"%H%% done", "15% done"}, // %% is a literal percent sign
+		{"%n%t", "\n\t"},
+		{"at the %d", "at the 02"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := Layout(tt.pattern)
+		if err != nil || got != tt.want {
+			t.Errorf("Layout(%q) = %q, %v; want %q", tt.pattern, got, err, tt.want)
+		}
+	}
+}
+
+func TestLayoutErrors(t *testing.T) {
+	tests := []struct{ pattern, want string }{
+		{"%Y-%m-%", "lone %"},
+		{"%Q", "unsupported conversion %Q"},
+		{"%Y %E", "unsupported conversion %E"},
+		{"%S%f", "%f must follow"},
+		{"%f", "%f must follow"},
+		{"week 1 of %Y", `literal "week 1 of "`},
+		{"%H PM", `literal " PM"`},
+		{"Monday %d", `"Mon"`},
+		{"%H:%M MST", `"MST"`},
+	}
+	for _, tt := range tests {
+		_, err := Layout(tt.pattern)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Layout(%q) error = %v, want one mentioning %s", tt.pattern, err, tt.want)
+		}
+		if _, err := Format(time.Now(), tt.pattern); err == nil {
+			t.Errorf("Format with %q did not fail", tt.pattern)
+		}
+		if _, err := Parse(tt.pattern, "x"); err == nil {
+			t.Errorf("Parse with %q did not fail", tt.pattern)
+		}
+	}
+}
+
+func TestFormatStrftime(t *testing.T) {
+	tm := time.Date(2020, 5, 3, 7, 8, 9, 123456789, time.FixedZone("CEST", 2*3600))
+	tests := []struct{ pattern, want string }{
+		{"%Y-%m-%d %H:%M:%S", "2020-05-03 07:08:09"},
+		{"%a %A %b %B %y", "Sun Sunday May May 20"},
+		{"%e|%j|%I|%l|%p", " 3|124|07|7|AM"},
+		{"%S.%f", "09.123456"},
+		{"%z %Z", "+0200 CEST"},
+		{"%H%%", "07%"},
+	}
+	for _, tt := range tests {
+		got, err := Format(tm, tt.pattern)
+		if err != nil || got != tt.want {
+			t.Errorf("Format(%q) = %q, %v; want %q", tt.pattern, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseStrftime(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		want           time.Time
+	}{
+		{"%Y-%m-%d", "2020-05-03", time.Date(2020, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{"%d/%m/%Y %H:%M", "03/05/2020 19:45", time.Date(2020, 5, 3, 19, 45, 0, 0, time.UTC)},
+		{"%b %e %I:%M%p", "May  3 07:45PM", time.Date(0, 5, 3, 19, 45, 0, 0, time.UTC)},
+		{"%F %T %z", "2020-05-03 07:08:09 +0200", time.Date(2020, 5, 3, 5, 8, 9, 0, time.UTC)},
+		{"%H:%M:%S.%f", "07:08:09.250000", time.Date(0, 1, 1, 7, 8, 9, 25e7, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.pattern, tt.value)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("Parse(%q, %q) = %v, %v; want %v", tt.pattern, tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := Parse("%Y-%m-%d", "2020-13-01"); err == nil {
+		t.Error("Parse accepted month 13")
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	// Each pattern holds everything needed to rebuild the time, so
+	// formatting and parsing back must give the same instant.
+	patterns := []string{"%Y-%m-%d %H:%M:%S %z", "%F %T.%f %z", "%a, %d %b %Y %I:%M:%S %p %z", "%A %B %e %Y %T %z"}
+	zone := time.FixedZone("", -7*3600)
+	for _, pattern := range patterns {
+		for _, tm := range []time.Time{
+			time.Date(2020, 5, 3, 7, 8, 9, 0, time.UTC),
+			time.Date(1999, 12, 31, 23, 59, 59, 0, zone),
+			time.Date(2024, 2, 29, 12, 0, 0, 0, zone),
+		} {
+			s, err := Format(tm, pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Parse(pattern, s)
+			if err != nil || !got.Equal(tm) {
+				t.Errorf("%q: %v formats as %q and parses back as %v, %v", pattern, tm, s, got, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// config holds the settings used to start the web server.
+// Every field can be set with a command line flag; the matching
+// environment variable is used as the flag's default value.
+type config struct {
+	Port         string        // port to listen on (WEBSERVER_PORT)
+	StaticRoot   string        // directory served for static files (WEBSERVER_STATIC_ROOT)
+	TemplateDir  string        // directory holding the page templates (WEBSERVER_TEMPLATE_DIR)
+	Embed        bool          // use the templates and static files embedded in the binary (WEBSERVER_EMBED)
+	Dev          bool          // reload templates on every request (WEBSERVER_DEV)
+	TLSCert      string        // path to the TLS certificate (WEBSERVER_TLS_CERT)
+	TLSKey       string        // path to the TLS private key (WEBSERVER_TLS_KEY)
+	ReadTimeout  time.Duration // maximum duration for reading the entire request
+	WriteTimeout time.Duration // maximum duration before timing out writes of the response
+	IdleTimeout  time.Duration // maximum time to wait for the next request on keep-alive connections
+	ShutdownWait time.Duration // how long Shutdown waits for in-flight requests to finish
+	MaxBodyBytes int64         // largest request body accepted (WEBSERVER_MAX_BODY)
+
+	SessionStore  string        // "memory" or "file" (WEBSERVER_SESSION_STORE)
+	SessionDir    string        // directory used by the file session store (WEBSERVER_SESSION_DIR)
+	SessionKey    string        // key used to sign session cookies (WEBSERVER_SESSION_KEY)
+	SessionIdle   time.Duration // a session expires after this long without requests
+	SessionMaxAge time.Duration // a session expires this long after it was created
+}
+
+// loadConfig parses the command line arguments into a config.
+// Environment variables are read first so that flags always win. A
+// malformed variable, or a certificate without a key, is an error rather
+// than something to quietly replace with a default.
+func loadConfig(args []string) (config, error) {
+	var c config
+	var env envReader
+	fs := flag.NewFlagSet("webserver", flag.ContinueOnError)
+	fs.StringVar(&c.Port, "port", env.string("WEBSERVER_PORT", "8084"), "port to listen on")
+	fs.StringVar(&c.StaticRoot, "static", env.string("WEBSERVER_STATIC_ROOT", "static"), "directory to serve static files from")
+	fs.StringVar(&c.TemplateDir, "templates", env.string("WEBSERVER_TEMPLATE_DIR", "templates"), "directory to load page templates from")
+	fs.BoolVar(&c.Embed, "embed", env.bool("WEBSERVER_EMBED", false), "use the templates and static files embedded in the binary")
+	fs.BoolVar(&c.Dev, "dev", env.bool("WEBSERVER_DEV", false), "development mode: reload templates on every request")
+	fs.StringVar(&c.TLSCert, "tls-cert", env.string("WEBSERVER_TLS_CERT", ""), "TLS certificate file (enables HTTPS together with -tls-key)")
+	fs.StringVar(&c.TLSKey, "tls-key", env.string("WEBSERVER_TLS_KEY", ""), "TLS private key file")
+	fs.DurationVar(&c.ReadTimeout, "read-timeout", env.duration("WEBSERVER_READ_TIMEOUT", 5*time.Second), "read timeout")
+	fs.DurationVar(&c.WriteTimeout, "write-timeout", env.duration("WEBSERVER_WRITE_TIMEOUT", 10*time.Second), "write timeout")
+	fs.DurationVar(&c.IdleTimeout, "idle-timeout", env.duration("WEBSERVER_IDLE_TIMEOUT", 120*time.Second), "idle timeout")
+	fs.DurationVar(&c.ShutdownWait, "shutdown-timeout", env.duration("WEBSERVER_SHUTDOWN_TIMEOUT", 15*time.Second), "graceful shutdown timeout")
+	fs.Int64Var(&c.MaxBodyBytes, "max-body", env.int64("WEBSERVER_MAX_BODY", 1<<20), "maximum request body size in bytes")
+	fs.StringVar(&c.SessionStore, "session-store", env.string("WEBSERVER_SESSION_STORE", "memory"), "where sessions are kept: memory or file")
+	fs.StringVar(&c.SessionDir, "session-dir", env.string("WEBSERVER_SESSION_DIR", "sessions"), "directory for the file session store")
+	fs.StringVar(&c.SessionKey, "session-key", env.string("WEBSERVER_SESSION_KEY", ""), "key used to sign session cookies (random if empty)")
+	fs.DurationVar(&c.SessionIdle, "session-idle", env.duration("WEBSERVER_SESSION_IDLE", 30*time.Minute), "session idle timeout")
+	fs.DurationVar(&c.SessionMaxAge, "session-max-age", env.duration("WEBSERVER_SESSION_MAX_AGE", 24*time.Hour), "session absolute timeout")
+	if err := errors.Join(env.errs...); err != nil {
+		return config{}, err
+	}
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return config{}, errors.New("-tls-cert and -tls-key must be given together")
+	}
+	return c, nil
+}
+
+// useTLS reports whether a certificate and key were given. loadConfig
+// makes sure there are either both or neither.
+func (c config) useTLS() bool {
+	return c.TLSCert != ""
+}
+
+// envReader reads flag defaults from the environment, keeping an error
+// for every variable that is set but cannot be parsed.
+type envReader struct {
+	errs []error
+}
+
+// string returns the value of the environment variable key,
+// or def if it is not set.
+func (e *envReader) string(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return def
+}
+
+// parse looks up key and, if it is set and not empty, parses it with
+// parse, recording an error if that fails.
+func (e *envReader) parse(key string, parse func(string) error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	if err := parse(v); err != nil {
+		e.errs = append(e.errs, fmt.Errorf("environment variable %s=%q: %w", key, v, err))
+	}
+}
+
+// duration returns the environment variable key parsed as a time.Duration,
+// or def if it is not set.
+func (e *envReader) duration(key string, def time.Duration) time.Duration {
+	e.parse(key, func(v string) (err error) {
+		def, err = time.ParseDuration(v)
+		return err
+	})
+	return def
+}
+
+// int64 returns the environment variable key parsed as an int64,
+// or def if it is not set.
+func (e *envReader) int64(key string, def int64) int64 {
+	e.parse(key, func(v string) (err error) {
+		def, err = strconv.ParseInt(v, 10, 64)
+		return err
+	})
+	return def
+}
+
+// bool returns the environment variable key parsed by strconv.ParseBool,
+// or def if it is not set.
+func (e *envReader) bool(key string, def bool) bool {
+	e.parse(key, func(v string) (err error) {
+		def, err = strconv.ParseBool(v)
+		return err
+	})
+	return def
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigEnv(t *testing.T) {
+	t.Setenv("WEBSERVER_READ_TIMEOUT", "7s")
+	t.Setenv("WEBSERVER_MAX_BODY", "2048")
+	t.Setenv("WEBSERVER_DEV", "true")
+	t.Setenv("WEBSERVER_EMBED", "")
+	c, err := loadConfig([]string{"-max-body", "512"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ReadTimeout != 7*time.Second || c.MaxBodyBytes != 512 || !c.Dev || c.Embed {
+		t.Errorf("got ReadTimeout %v, MaxBodyBytes %d, Dev %t, Embed %t; want 7s, 512 (the flag wins), true, false",
+			c.ReadTimeout, c.MaxBodyBytes, c.Dev, c.Embed)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want string // in the error
+	}{
+		{"bad duration", map[string]string{"WEBSERVER_IDLE_TIMEOUT": "2 minutes"}, nil, "WEBSERVER_IDLE_TIMEOUT"},
+		{"bad int", map[string]string{"WEBSERVER_MAX_BODY": "1MB"}, nil, "WEBSERVER_MAX_BODY"},
+		{"bad bool", map[string]string{"WEBSERVER_DEV": "yes"}, nil, "WEBSERVER_DEV"},
+		{"both reported", map[string]string{"WEBSERVER_MAX_BODY": "x", "WEBSERVER_SESSION_IDLE": "y"}, nil, "WEBSERVER_SESSION_IDLE"},
+		{"cert only", nil, []string{"-tls-cert", "cert.pem"}, "-tls-key"},
+		{"key only", map[string]string{"WEBSERVER_TLS_KEY": "key.pem"}, nil, "-tls-cert"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := loadConfig(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loadConfig = %v, want an error mentioning %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigTLS(t *testing.T) {
+	c, err := loadConfig([]string{"-tls-cert", "cert.pem", "-tls-key", "key.pem"})
+	if err != nil || !c.useTLS() {
+		t.Errorf("cert and key: useTLS %t, err %v", c.useTLS(), err)
+	}
+	if c, err := loadConfig(nil); err != nil || c.useTLS() {
+		t.Errorf("neither: useTLS %t, err %v", c.useTLS(), err)
+	}
+}
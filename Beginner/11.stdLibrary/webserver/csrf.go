@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	csrfFormField = "csrf_token" // form field the token is posted in
+	csrfTokenTTL  = time.Hour    // how long an issued token stays valid
+	csrfMaxTokens = 16           // tokens kept per session; older ones are dropped
+)
+
+// csrfStore remembers which tokens were issued to which session.
+// A token is removed as soon as it is used, so a replayed form is rejected.
+type csrfStore struct {
+	mu     sync.Mutex
+	tokens map[string]map[string]time.Time // session id -> token -> expiry
+}
+
+func newCSRFStore() *csrfStore {
+	return &csrfStore{tokens: map[string]map[string]time.Time{}}
+}
+
+// issue creates a new token for session id.
+func (s *csrfStore) issue(id string) string {
+	tok := randomToken()
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	toks := s.tokens[id]
+	if toks == nil {
+		toks = map[string]time.Time{}
+		s.tokens[id] = toks
+	}
+	// Drop expired tokens, and the oldest one if the session has too many
+	// (for example because the form was opened in a lot of tabs).
+	var oldest string
+	for t, exp := range toks {
+		if now.After(exp) {
+			delete(toks, t)
+			continue
+		}
+		if oldest == "" || exp.Before(toks[oldest]) {
+			oldest = t
+		}
+	}
+	if len(toks) >= csrfMaxTokens {
+		delete(toks, oldest)
+	}
+	toks[tok] = now.Add(csrfTokenTTL)
+	return tok
+}
+
+// consume reports whether tok was issued to session id and is still
+// valid. The token can not be used again afterwards.
+func (s *csrfStore) consume(id, tok string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for t, exp := range s.tokens[id] {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(tok)) == 1 {
+			delete(s.tokens[id], t)
+			return time.Now().Before(exp)
+		}
+	}
+	return false
+}
+
+// sweep deletes expired tokens, and sessions left without any, so that
+// tokens issued to sessions that never post their form do not pile up.
+func (s *csrfStore) sweep() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, toks := range s.tokens {
+		for t, exp := range toks {
+			if now.After(exp) {
+				delete(toks, t)
+			}
+		}
+		if len(toks) == 0 {
+			delete(s.tokens, id)
+		}
+	}
+}
+
+// csrfKey is the context key under which csrfProtect stores the
+// function that issues a token for the current request.
+type csrfKey struct{}
+
+// csrfToken returns a fresh token for the session of r, or "" when r
+// did not pass through csrfProtect. Issuing a token starts the session
+// if it is new, which sends its cookie, so csrfToken must be called
+// before the response is written.
+func csrfToken(r *http.Request) string {
+	if issue, ok := r.Context().Value(csrfKey{}).(func() string); ok {
+		return issue()
+	}
+	return ""
+}
+
+// csrfProtect rejects POST requests whose csrf_token field was not
+// issued to the session making them. It must run inside
+// sessionManager.middleware. The body of every request is limited to
+// maxBody bytes; larger requests get a 413 response.
+func csrfProtect(store *csrfStore, maxBody int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBody)
+		sess := sessionFrom(r)
+
+		if r.Method == http.MethodPost {
+			if err := r.ParseForm(); err != nil {
+				formError(w, err)
+				return
+			}
+			if sess == nil || sess.ID == "" || !store.consume(sess.ID, r.PostFormValue(csrfFormField)) {
+				http.Error(w, "403 invalid or missing CSRF token", http.StatusForbidden)
+				return
+			}
+		}
+
+		issue := func() string {
+			if sess == nil {
+				return ""
+			}
+			return store.issue(sess.persist())
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), csrfKey{}, issue)))
+	})
+}
+
+// formError responds to a failed ParseForm, using 413 when the body
+// was larger than allowed.
+func formError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "413 request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, "ParseForm() error: "+err.Error(), http.StatusBadRequest)
+}
+
+// randomToken returns 32 random bytes encoded for use in a cookie or form.
+func randomToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestHandler returns the full handler with embedded templates and a
+// small body limit.
+func newTestHandler(t *testing.T) http.Handler {
+	t.Helper()
+	c, err := loadConfig([]string{"-embed", "-max-body", "1024", "-session-store", "memory", "-session-key", "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, stop, err := newHandler(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(stop)
+	return h
+}
+
+var tokenField = regexp.MustCompile(`name="csrf_token" type="hidden" value="([^"]+)"`)
+
+// openForm fetches the form page and returns the session cookie and the
+// CSRF token on the page.
+func openForm(t *testing.T, h http.Handler) (*http.Cookie, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/form.html", nil))
+	m := tokenField.FindStringSubmatch(rec.Body.String())
+	if m == nil {
+		t.Fatalf("no csrf_token in form page:\n%s", rec.Body)
+	}
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == sessionCookie {
+			return c, m[1]
+		}
+	}
+	t.Fatal("form page did not start a session")
+	return nil, ""
+}
+
+func postForm(h http.Handler, cookie *http.Cookie, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/form", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCSRFValidSubmission(t *testing.T) {
+	h := newTestHandler(t)
+	cookie, tok := openForm(t, h)
+	rec := postForm(h, cookie, url.Values{"csrf_token": {tok}, "name": {"gopher"}, "age": {"13"}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("valid submission got %d, want 200:\n%s", rec.Code, rec.Body)
+	}
+}
+
+func TestCSRFReplayedSubmission(t *testing.T) {
+	h := newTestHandler(t)
+	cookie, tok := openForm(t, h)
+	form := url.Values{"csrf_token": {tok}, "name": {"gopher"}}
+	if rec := postForm(h, cookie, form); rec.Code != http.StatusOK {
+		t.Fatalf("first submission got %d, want 200", rec.Code)
+	}
+	if rec := postForm(h, cookie, form); rec.Code != http.StatusForbidden {
+		t.Fatalf("replayed submission got %d, want 403", rec.Code)
+	}
+}
+
+func TestCSRFMissingToken(t *testing.T) {
+	h := newTestHandler(t)
+	cookie, _ := openForm(t, h)
+	if rec := postForm(h, cookie, url.Values{"name": {"gopher"}}); rec.Code != http.StatusForbidden {
+		t.Fatalf("submission without token got %d, want 403", rec.Code)
+	}
+}
+
+func TestCSRFTokenFromOtherSession(t *testing.T) {
+	h := newTestHandler(t)
+	_, tok := openForm(t, h)
+	other, _ := openForm(t, h)
+	if rec := postForm(h, other, url.Values{"csrf_token": {tok}, "name": {"gopher"}}); rec.Code != http.StatusForbidden {
+		t.Fatalf("token of another session got %d, want 403", rec.Code)
+	}
+	if rec := postForm(h, nil, url.Values{"csrf_token": {tok}, "name": {"gopher"}}); rec.Code != http.StatusForbidden {
+		t.Fatalf("token without a session got %d, want 403", rec.Code)
+	}
+}
+
+func TestCSRFOversizedSubmission(t *testing.T) {
+	h := newTestHandler(t)
+	cookie, tok := openForm(t, h)
+	form := url.Values{"csrf_token": {tok}, "name": {strings.Repeat("x", 2048)}}
+	if rec := postForm(h, cookie, form); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized submission got %d, want 413", rec.Code)
+	}
+}
+
+func TestNoSessionForPagesWithoutForms(t *testing.T) {
+	h := newTestHandler(t)
+	for _, path := range []string{"/", "/hello", "/static/style.css"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if c := rec.Result().Cookies(); len(c) != 0 {
+			t.Errorf("GET %s set cookies %v", path, c)
+		}
+	}
+}
+
+func TestCSRFSweep(t *testing.T) {
+	s := newCSRFStore()
+	s.issue("a")
+	s.issue("b")
+	for tok := range s.tokens["a"] {
+		s.tokens["a"][tok] = time.Now().Add(-time.Second)
+	}
+	s.sweep()
+	if _, ok := s.tokens["a"]; ok {
+		t.Error("sweep kept a session whose tokens all expired")
+	}
+	if len(s.tokens["b"]) != 1 {
+		t.Error("sweep removed a valid token")
+	}
+}
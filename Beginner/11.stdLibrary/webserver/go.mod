@@ -0,0 +1,3 @@
+module github.com/learning-go-book/webserver
+
+go 1.21
@@ -4,34 +4,76 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 )
 
+// app holds what the handlers share.
+type app struct {
+	tmpl *renderer
+}
+
+// person is the data submitted through the form on "/form.html".
+type person struct {
+	Name string
+	Age  string
+}
+
 // homeHandler handles requests made to the root URL ("/").
-func homeHandler(w http.ResponseWriter, r *http.Request) {
-	fmt.Printf("HOME PAGE")
+func (a *app) homeHandler(w http.ResponseWriter, r *http.Request) {
+	// "/" matches every path that no other handler claimed.
+	if r.URL.Path != "/" && r.URL.Path != "/index.html" {
+		http.Error(w, "404 not found", http.StatusNotFound)
+		return
+	}
+	a.tmpl.render(w, r, http.StatusOK, "index.html", nil)
+}
+
+// formPageHandler renders the form that posts to "/form".
+func (a *app) formPageHandler(w http.ResponseWriter, r *http.Request) {
+	a.tmpl.render(w, r, http.StatusOK, "form.html", nil)
 }
 
 // formHandler processes form data sent via POST request to the "/form" endpoint.
-func formHandler(w http.ResponseWriter, r *http.Request) {
+func (a *app) formHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method is not supported", http.StatusMethodNotAllowed)
+		return
+	}
+
 	// ParseForm parses the raw query from the URL and updates r.Form.
 	if err := r.ParseForm(); err != nil {
 		// If there's an error parsing the form, it writes an error message to the response.
-		fmt.Fprintf(w, "ParseForm() error: %v", err)
+		formError(w, err)
 		return
 	}
-	fmt.Printf("POST request was successful")
 
 	// Retrieving values from the form data.
-	name := r.FormValue("name")
-	age := r.FormValue("age")
+	p := person{
+		Name: strings.TrimSpace(r.FormValue("name")),
+		Age:  r.FormValue("age"),
+	}
+	if p.Name == "" {
+		// Send the user back to the form with a message explaining why.
+		setFlash(w, "Please enter your name.")
+		http.Redirect(w, r, "/form.html", http.StatusSeeOther)
+		return
+	}
+	log.Printf("POST request was successful")
 
-	// Printing the received form values.
-	fmt.Fprintf(w, "Name: %s\n", name)
-	fmt.Fprintf(w, "Age: %s\n", age)
+	// Entering a name counts as logging in: give the session a new id
+	// and remember the name for the next visit.
+	if sess := sessionFrom(r); sess != nil {
+		renewID(w, r)
+		sess.Set("name", p.Name)
+	}
+
+	// Rendering the received form values; the template escapes them.
+	a.tmpl.render(w, r, http.StatusOK, "result.html", p)
 }
 
 // helloHandler handles requests to the "/hello" endpoint with specific checks.
-func helloHandler(w http.ResponseWriter, r *http.Request) {
+func (a *app) helloHandler(w http.ResponseWriter, r *http.Request) {
 	// If the URL path is not "/hello", respond with a 404 error.
 	if r.URL.Path != "/hello" {
 		http.Error(w, "404 not found", http.StatusNotFound)
@@ -44,18 +86,57 @@ func helloHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Respond with a simple "HELLO WORLD" message.
+	// Greet a returning user by the name they entered on "/form.html",
+	// everyone else gets a simple "HELLO WORLD" message.
+	if sess := sessionFrom(r); sess != nil && sess.Get("name") != "" {
+		fmt.Fprintf(w, "HELLO %s, welcome back", sess.Get("name"))
+		return
+	}
 	fmt.Fprintf(w, "HELLO WORLD")
 }
 
 func main() {
-	// Setting up handlers for different routes.
-	fileServer := http.FileServer(http.Dir("."))
-	http.Handle("/", fileServer)            // Handler for the root URL
-	http.HandleFunc("/form", formHandler)   // Handler for the "/form" endpoint
-	http.HandleFunc("/hello", helloHandler) // Handler for the "/hello" endpoint
-	fmt.Println("Starting Server at port 8084")
-	if err := http.ListenAndServe(":8084", nil); err != nil {
+	c, err := loadConfig(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	handler, stop, err := newHandler(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stop()
+	if err := run(c, handler); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// newHandler builds the handler serving every route, with sessions and
+// CSRF protection in front. stop ends the background sweeping of expired
+// sessions and tokens.
+func newHandler(c config) (handler http.Handler, stop func(), err error) {
+	tmpl, err := newRenderer(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	static, err := newStaticHandler(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	sessions, err := newSessionManager(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	csrf := newCSRFStore()
+	a := &app{tmpl: tmpl}
+
+	// Setting up handlers for different routes.
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", a.homeHandler)                           // Handler for the root URL
+	mux.HandleFunc("/form.html", a.formPageHandler)              // Handler for the form page
+	mux.Handle("/static/", http.StripPrefix("/static/", static)) // Handler for stylesheets and other assets
+	mux.HandleFunc("/form", a.formHandler)                       // Handler for the "/form" endpoint
+	mux.HandleFunc("/hello", a.helloHandler)                     // Handler for the "/hello" endpoint
+
+	stop = sweepEvery(sweepInterval, sessions.sweep, csrf.sweep)
+	return sessions.middleware(csrfProtect(csrf, c.MaxBodyBytes, mux)), stop, nil
+}
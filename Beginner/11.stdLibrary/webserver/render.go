@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// sharedTemplates are parsed together with every page: the layout that
+// wraps the page and the partials it uses.
+var sharedTemplates = []string{"layout.html", "flash.html"}
+
+// pageData is what every page template is executed with.
+type pageData struct {
+	Flash string // one-time message shown above the page content
+	Data  any    // page specific data
+
+	csrf func() string
+}
+
+// CSRFToken returns a token that a form must post back in the csrf_token
+// field. Tokens are only issued, and sessions only started, for pages
+// whose template asks for one.
+func (pd pageData) CSRFToken() string {
+	return pd.csrf()
+}
+
+// renderer executes the page templates inside the shared layout.
+// html/template escapes everything written through it, so values taken
+// from a request can be passed straight to a page.
+type renderer struct {
+	fsys fs.FS
+	dev  bool // reparse the templates on every request (hot reload)
+
+	mu    sync.RWMutex
+	cache map[string]*template.Template
+}
+
+// newRenderer returns a renderer for the templates in c.TemplateDir, or the
+// embedded ones when c.Embed is set.
+func newRenderer(c config) (*renderer, error) {
+	var fsys fs.FS = os.DirFS(c.TemplateDir)
+	if c.Embed {
+		sub, err := fs.Sub(embeddedFiles, "templates")
+		if err != nil {
+			return nil, err
+		}
+		fsys = sub
+	}
+	return &renderer{fsys: fsys, dev: c.Dev, cache: map[string]*template.Template{}}, nil
+}
+
+// lookup returns the parsed template set for page.
+// Outside dev mode the set is parsed once and cached.
+func (rd *renderer) lookup(page string) (*template.Template, error) {
+	if rd.dev {
+		return rd.parse(page)
+	}
+	rd.mu.RLock()
+	t, ok := rd.cache[page]
+	rd.mu.RUnlock()
+	if ok {
+		return t, nil
+	}
+
+	t, err := rd.parse(page)
+	if err != nil {
+		return nil, err
+	}
+	rd.mu.Lock()
+	rd.cache[page] = t
+	rd.mu.Unlock()
+	return t, nil
+}
+
+// parse reads the shared templates and page from the template directory.
+func (rd *renderer) parse(page string) (*template.Template, error) {
+	files := append([]string{page}, sharedTemplates...)
+	return template.New(page).ParseFS(rd.fsys, files...)
+}
+
+// render writes page to w with status. Any flash message stored for the
+// client is consumed and shown once.
+// The page is executed into a buffer first so that a template error
+// results in a clean 500 instead of half a page.
+func (rd *renderer) render(w http.ResponseWriter, r *http.Request, status int, page string, data any) {
+	t, err := rd.lookup(page)
+	if err != nil {
+		log.Printf("template %s: %v", page, err)
+		http.Error(w, "500 internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	pd := pageData{Flash: popFlash(w, r), Data: data, csrf: func() string { return csrfToken(r) }}
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "layout", pd); err != nil {
+		log.Printf("template %s: %v", page, err)
+		http.Error(w, "500 internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// flashCookie is the name of the cookie carrying a flash message
+// across a redirect.
+const flashCookie = "flash"
+
+// setFlash stores msg so that it is shown on the next rendered page.
+func setFlash(w http.ResponseWriter, msg string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     flashCookie,
+		Value:    base64.RawURLEncoding.EncodeToString([]byte(msg)),
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// popFlash returns the pending flash message, if any, and deletes it.
+func popFlash(w http.ResponseWriter, r *http.Request) string {
+	c, err := r.Cookie(flashCookie)
+	if err != nil {
+		return ""
+	}
+	http.SetCookie(w, &http.Cookie{Name: flashCookie, Path: "/", MaxAge: -1})
+	msg, err := base64.RawURLEncoding.DecodeString(c.Value)
+	if err != nil {
+		return ""
+	}
+	return string(msg)
+}
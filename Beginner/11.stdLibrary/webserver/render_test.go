@@ -0,0 +1,115 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testTemplates is a small template set shaped like the real one.
+func testTemplates() fstest.MapFS {
+	return fstest.MapFS{
+		"layout.html": {Data: []byte(`{{define "layout"}}<main>{{template "flash" .}}{{template "content" .}}</main>{{end}}`)},
+		"flash.html":  {Data: []byte(`{{define "flash"}}{{with .Flash}}<p class="flash">{{.}}</p>{{end}}{{end}}`)},
+		"page.html":   {Data: []byte(`{{define "content"}}<p>{{.Data}}</p>{{end}}`)},
+		"broken.html": {Data: []byte(`{{define "content"}}<p>{{.Data.Missing}}</p>{{end}}`)},
+	}
+}
+
+// renderPage renders page with data for a request carrying cookies.
+func renderPage(rd *renderer, page string, data any, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/", nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rec := httptest.NewRecorder()
+	rd.render(rec, req, http.StatusOK, page, data)
+	return rec
+}
+
+func TestRenderLayout(t *testing.T) {
+	rd := &renderer{fsys: testTemplates(), cache: map[string]*template.Template{}}
+	rec := renderPage(rd, "page.html", "<b>Bob</b>")
+	if want := "<main><p>&lt;b&gt;Bob&lt;/b&gt;</p></main>"; rec.Body.String() != want {
+		t.Errorf("body = %q, want %q", rec.Body.String(), want)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	// A failing template gives a 500 and none of the page.
+	rec = renderPage(rd, "broken.html", "a string has no fields")
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "<main>") {
+		t.Errorf("broken page: %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := renderPage(rd, "missing.html", nil); rec.Code != http.StatusInternalServerError {
+		t.Errorf("missing page: %d", rec.Code)
+	}
+}
+
+func TestRenderFlash(t *testing.T) {
+	rd := &renderer{fsys: testTemplates(), cache: map[string]*template.Template{}}
+
+	// setFlash stores the message in a cookie for the next page.
+	rec := httptest.NewRecorder()
+	setFlash(rec, "Please enter your name.")
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != flashCookie {
+		t.Fatalf("setFlash set %v", cookies)
+	}
+
+	// The next page shows it and deletes the cookie.
+	rec = renderPage(rd, "page.html", "x", cookies[0])
+	if !strings.Contains(rec.Body.String(), `<p class="flash">Please enter your name.</p>`) {
+		t.Errorf("flash not shown: %q", rec.Body.String())
+	}
+	deleted := rec.Result().Cookies()
+	if len(deleted) != 1 || deleted[0].Name != flashCookie || deleted[0].MaxAge >= 0 {
+		t.Errorf("flash cookie not deleted: %v", deleted)
+	}
+
+	// The page after that, without the cookie, shows nothing.
+	if rec := renderPage(rd, "page.html", "x"); strings.Contains(rec.Body.String(), "flash") || len(rec.Result().Cookies()) != 0 {
+		t.Errorf("flash shown twice: %q, cookies %v", rec.Body.String(), rec.Result().Cookies())
+	}
+
+	// A cookie that is not valid base64 is dropped without showing anything.
+	rec = renderPage(rd, "page.html", "x", &http.Cookie{Name: flashCookie, Value: "!!!"})
+	if strings.Contains(rec.Body.String(), "flash") || len(rec.Result().Cookies()) != 1 {
+		t.Errorf("bad flash cookie: %q, cookies %v", rec.Body.String(), rec.Result().Cookies())
+	}
+}
+
+func TestRenderReload(t *testing.T) {
+	for _, dev := range []bool{false, true} {
+		fsys := testTemplates()
+		rd := &renderer{fsys: fsys, dev: dev, cache: map[string]*template.Template{}}
+		renderPage(rd, "page.html", "x")
+		fsys["page.html"] = &fstest.MapFile{Data: []byte(`{{define "content"}}<h1>{{.Data}}</h1>{{end}}`)}
+		got := renderPage(rd, "page.html", "x").Body.String()
+		want := "<main><p>x</p></main>" // cached
+		if dev {
+			want = "<main><h1>x</h1></main>" // reparsed
+		}
+		if got != want {
+			t.Errorf("dev %t: after editing the page got %q, want %q", dev, got, want)
+		}
+	}
+}
+
+func TestRenderEmbedded(t *testing.T) {
+	rd, err := newRenderer(config{Embed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := renderPage(rd, "result.html", struct {
+		Name string
+		Age  int
+	}{"Ada", 36})
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Name: Ada") {
+		t.Errorf("result.html: %d %q", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// newServer builds an http.Server for handler using the timeouts from c.
+// Unlike http.ListenAndServe, a server created this way never waits
+// forever on a slow client.
+func newServer(c config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:         ":" + c.Port,
+		Handler:      handler,
+		ReadTimeout:  c.ReadTimeout,
+		WriteTimeout: c.WriteTimeout,
+		IdleTimeout:  c.IdleTimeout,
+	}
+}
+
+// serve accepts connections on l until ctx is cancelled.
+// When that happens it calls Shutdown, which stops accepting new
+// connections and waits (up to c.ShutdownWait) for in-flight requests to finish.
+func serve(ctx context.Context, c config, srv *http.Server, l net.Listener) error {
+	errCh := make(chan error, 1)
+	go func() {
+		var err error
+		if c.useTLS() {
+			err = srv.ServeTLS(l, c.TLSCert, c.TLSKey)
+		} else {
+			err = srv.Serve(l)
+		}
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		// Serve returned on its own, so something went wrong.
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), c.ShutdownWait)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	// After Shutdown, Serve always returns ErrServerClosed.
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// run listens on the configured port and serves handler until
+// SIGINT or SIGTERM is received.
+func run(c config, handler http.Handler) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := newServer(c, handler)
+	l, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("Starting Server at port %s (tls=%v)", c.Port, c.useTLS())
+	return serve(ctx, c, srv, l)
+}
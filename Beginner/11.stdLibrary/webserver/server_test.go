@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// startServe runs serve on a local port with handler and returns the
+// base URL, a function that starts the shutdown and a channel receiving
+// what serve returned.
+func startServe(t *testing.T, c config, handler http.Handler) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	done := make(chan error, 1)
+	go func() { done <- serve(ctx, c, newServer(c, handler), l) }()
+	return "http://" + l.Addr().String(), cancel, done
+}
+
+// blockingHandler signals on started when a request arrives and answers
+// it only once release is closed.
+func blockingHandler(started chan<- struct{}, release <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		io.WriteString(w, "done")
+	})
+}
+
+type result struct {
+	status int
+	body   string
+	err    error
+}
+
+func get(url string) <-chan result {
+	ch := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			ch <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		ch <- result{status: resp.StatusCode, body: string(b), err: err}
+	}()
+	return ch
+}
+
+func TestServeFinishesInFlightRequest(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	url, shutdown, done := startServe(t, config{ShutdownWait: 5 * time.Second}, blockingHandler(started, release))
+
+	res := get(url)
+	<-started
+	shutdown()
+
+	// serve must wait for the request that is still being handled.
+	select {
+	case err := <-done:
+		t.Fatalf("serve returned %v with a request in flight", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// New connections are refused once shutdown has begun.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", url[len("http://"):])
+		if err != nil {
+			break
+		}
+		conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("server still accepts connections during shutdown")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	close(release)
+	r := <-res
+	if r.err != nil || r.status != http.StatusOK || r.body != "done" {
+		t.Fatalf("in-flight request got %d %q, %v; want 200 \"done\"", r.status, r.body, r.err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("serve returned %v, want nil", err)
+	}
+}
+
+func TestServeShutdownTimeout(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	defer close(release)
+	url, shutdown, done := startServe(t, config{ShutdownWait: 50 * time.Millisecond}, blockingHandler(started, release))
+
+	get(url)
+	<-started
+	shutdown()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("serve returned %v, want context.DeadlineExceeded", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not give up after ShutdownWait")
+	}
+}
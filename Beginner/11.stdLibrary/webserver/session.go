@@ -0,0 +1,413 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errNoSession is returned by a sessionStore when the id is unknown.
+var errNoSession = errors.New("session not found")
+
+// session is the server side state kept for one client.
+//
+// A new session is not stored, and no cookie is sent for it, until
+// something is written to it, so anonymous visitors and static file
+// requests cost nothing.
+type session struct {
+	ID       string            `json:"id"` // "" until the session is first written
+	Values   map[string]string `json:"values"`
+	Created  time.Time         `json:"created"`
+	LastSeen time.Time         `json:"last_seen"`
+
+	start func() // gives a new session its id and cookie
+}
+
+// Get returns the value stored under key, or "".
+func (s *session) Get(key string) string {
+	return s.Values[key]
+}
+
+// Set stores value under key. The session is saved after the request.
+// On a new session Set sends the session cookie, so it must be called
+// before the handler writes its response.
+func (s *session) Set(key, value string) {
+	s.Values[key] = value
+	s.persist()
+}
+
+// persist makes sure s will be saved, giving it an id and sending its
+// cookie if it is new, and returns the id.
+func (s *session) persist() string {
+	if s.ID == "" && s.start != nil {
+		s.start()
+	}
+	return s.ID
+}
+
+// clone returns a copy of s that shares no map with it.
+func (s *session) clone() *session {
+	c := *s
+	c.Values = maps.Clone(s.Values)
+	c.start = nil
+	return &c
+}
+
+// sessionStore keeps sessions on the server side.
+// Only the session id ever leaves the server, inside a signed cookie.
+type sessionStore interface {
+	Load(id string) (*session, error)
+	Save(s *session) error
+	Delete(id string) error
+	// Sweep deletes every stored session for which expired is true.
+	Sweep(expired func(*session) bool) error
+}
+
+// memoryStore keeps sessions in a map; they are lost on restart.
+type memoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{sessions: map[string]*session{}}
+}
+
+func (m *memoryStore) Load(id string) (*session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, errNoSession
+	}
+	return s.clone(), nil
+}
+
+func (m *memoryStore) Save(s *session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[s.ID] = s.clone()
+	return nil
+}
+
+func (m *memoryStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+func (m *memoryStore) Sweep(expired func(*session) bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, s := range m.sessions {
+		if expired(s) {
+			delete(m.sessions, id)
+		}
+	}
+	return nil
+}
+
+// fileStore keeps every session as a JSON file in dir, so sessions
+// survive a restart of the server.
+type fileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func newFileStore(dir string) (*fileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+// path returns the file for id. Ids are generated by randomToken, so they
+// only contain URL safe base64 characters; anything else is rejected.
+func (f *fileStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", fmt.Errorf("invalid session id %q", id)
+	}
+	return filepath.Join(f.dir, id+".json"), nil
+}
+
+func (f *fileStore) Load(id string) (*session, error) {
+	p, err := f.path(id)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNoSession
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s session
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (f *fileStore) Save(s *session) error {
+	p, err := f.path(s.ID)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// Write to a temporary file first so a crash never leaves half a session behind.
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func (f *fileStore) Delete(id string) error {
+	p, err := f.path(id)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Sweep also removes files that do not hold a session, such as ones left
+// half written by a crash, so the directory cannot fill up with them.
+func (f *fileStore) Sweep(expired func(*session) bool) error {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		s, err := f.Load(id)
+		if errors.Is(err, errNoSession) {
+			continue // deleted meanwhile
+		}
+		if err == nil && !expired(s) {
+			continue
+		}
+		if err := f.Delete(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sessionManager ties sessions to clients with a signed cookie.
+type sessionManager struct {
+	store    sessionStore
+	key      []byte        // HMAC key used to sign the cookie
+	idle     time.Duration // a session expires after this long without requests
+	absolute time.Duration // a session expires this long after it was created
+	secure   bool          // only send the cookie over HTTPS
+}
+
+// sessionCookie is the name of the cookie holding the signed session id.
+const sessionCookie = "session"
+
+// newSessionManager builds a sessionManager from c.
+func newSessionManager(c config) (*sessionManager, error) {
+	var store sessionStore
+	switch c.SessionStore {
+	case "memory":
+		store = newMemoryStore()
+	case "file":
+		fs, err := newFileStore(c.SessionDir)
+		if err != nil {
+			return nil, err
+		}
+		store = fs
+	default:
+		return nil, fmt.Errorf("unknown session store %q (want memory or file)", c.SessionStore)
+	}
+
+	key := []byte(c.SessionKey)
+	if len(key) == 0 {
+		// Without a configured key, sessions do not survive a restart.
+		log.Println("no session key configured, generating a random one")
+		key = []byte(randomToken())
+	}
+	return &sessionManager{
+		store:    store,
+		key:      key,
+		idle:     c.SessionIdle,
+		absolute: c.SessionMaxAge,
+		secure:   c.useTLS(),
+	}, nil
+}
+
+// sign returns the cookie value for id: the id and its HMAC.
+func (m *sessionManager) sign(id string) string {
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write([]byte(id))
+	return id + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify returns the id from a cookie value made by sign.
+func (m *sessionManager) verify(value string) (string, bool) {
+	id, _, ok := strings.Cut(value, ".")
+	if !ok {
+		return "", false
+	}
+	return id, hmac.Equal([]byte(m.sign(id)), []byte(value))
+}
+
+// expired reports whether s is too old to use at now.
+func (m *sessionManager) expired(s *session, now time.Time) bool {
+	return now.Sub(s.LastSeen) >= m.idle || now.Sub(s.Created) >= m.absolute
+}
+
+// sweep deletes the expired sessions from the store. Sessions that are
+// never visited again would otherwise stay there for ever.
+func (m *sessionManager) sweep() {
+	now := time.Now()
+	if err := m.store.Sweep(func(s *session) bool { return m.expired(s, now) }); err != nil {
+		log.Printf("sweeping sessions: %v", err)
+	}
+}
+
+// load returns the session for r, or a new one, without an id, if the
+// cookie is missing, forged or the session has expired.
+func (m *sessionManager) load(r *http.Request) *session {
+	now := time.Now()
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		if id, ok := m.verify(c.Value); ok {
+			s, err := m.store.Load(id)
+			switch {
+			case err == nil && !m.expired(s, now):
+				return s
+			case err == nil:
+				m.store.Delete(id) // expired
+			case !errors.Is(err, errNoSession):
+				log.Printf("loading session: %v", err)
+			}
+		}
+	}
+	return &session{Values: map[string]string{}, Created: now}
+}
+
+// setCookie sends the signed id of s to the client.
+func (m *sessionManager) setCookie(w http.ResponseWriter, s *session) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    m.sign(s.ID),
+		Path:     "/",
+		Expires:  s.Created.Add(m.absolute),
+		HttpOnly: true,
+		Secure:   m.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// sessionKey is the context key for the current *session.
+type sessionKey struct{}
+
+// sessionFrom returns the session of r. It is nil if r did not pass
+// through sessionManager.middleware.
+func sessionFrom(r *http.Request) *session {
+	s, _ := r.Context().Value(sessionKey{}).(*session)
+	return s
+}
+
+// renewID gives the session of r a new id, which should be done whenever
+// the user logs in so that an id known before the login becomes useless.
+// The cookie is sent right away, so renewID must be called before the
+// handler writes its response.
+func renewID(w http.ResponseWriter, r *http.Request) {
+	s := sessionFrom(r)
+	if s == nil {
+		return
+	}
+	m, _ := r.Context().Value(sessionManagerKey{}).(*sessionManager)
+	if m == nil {
+		return
+	}
+	if s.ID != "" {
+		m.store.Delete(s.ID)
+	}
+	s.ID = randomToken()
+	m.setCookie(w, s)
+}
+
+// sessionManagerKey is the context key for the *sessionManager
+// that loaded the current session.
+type sessionManagerKey struct{}
+
+// middleware loads the session before next runs and saves it afterwards,
+// unless it is a new session nothing was written to.
+func (m *sessionManager) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := m.load(r)
+		s.LastSeen = time.Now()
+		s.start = func() {
+			s.ID = randomToken()
+			m.setCookie(w, s)
+		}
+
+		ctx := context.WithValue(r.Context(), sessionKey{}, s)
+		ctx = context.WithValue(ctx, sessionManagerKey{}, m)
+		next.ServeHTTP(w, r.WithContext(ctx))
+
+		if s.ID == "" {
+			return
+		}
+		if err := m.store.Save(s); err != nil {
+			log.Printf("saving session: %v", err)
+		}
+	})
+}
+
+// sweepInterval is how often expired sessions and CSRF tokens are
+// removed.
+const sweepInterval = 5 * time.Minute
+
+// sweepEvery calls each of sweeps every interval until stop is called.
+func sweepEvery(interval time.Duration, sweeps ...func()) (stop func()) {
+	t := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-t.C:
+				for _, sweep := range sweeps {
+					sweep()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		t.Stop()
+		close(done)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestSessions(store sessionStore) *sessionManager {
+	return &sessionManager{store: store, key: []byte("test key"), idle: time.Hour, absolute: 24 * time.Hour}
+}
+
+// countSessions returns the number of sessions in store, counting them
+// with a Sweep that deletes nothing.
+func countSessions(t *testing.T, store sessionStore) int {
+	t.Helper()
+	n := 0
+	if err := store.Sweep(func(*session) bool { n++; return false }); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestSessionNotStoredUntilWritten(t *testing.T) {
+	store := newMemoryStore()
+	m := newTestSessions(store)
+	h := m.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sessionFrom(r).Get("name") != "" {
+			t.Error("new session has a value")
+		}
+	}))
+
+	for i := 0; i < 10; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/static/style.css", nil))
+		if c := rec.Result().Cookies(); len(c) != 0 {
+			t.Fatalf("anonymous request got cookies %v", c)
+		}
+	}
+	if n := countSessions(t, store); n != 0 {
+		t.Fatalf("store holds %d sessions after anonymous requests, want 0", n)
+	}
+}
+
+func TestSessionCreatedOnWrite(t *testing.T) {
+	store := newMemoryStore()
+	m := newTestSessions(store)
+	h := m.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := sessionFrom(r)
+		if r.URL.Path == "/set" {
+			s.Set("name", "gopher")
+		}
+		w.Write([]byte(s.Get("name")))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/set", nil))
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != sessionCookie {
+		t.Fatalf("writing a session sent cookies %v, want one %q cookie", cookies, sessionCookie)
+	}
+
+	req := httptest.NewRequest("GET", "/get", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Body.String(); got != "gopher" {
+		t.Fatalf("next request saw name %q, want %q", got, "gopher")
+	}
+	if n := countSessions(t, store); n != 1 {
+		t.Fatalf("store holds %d sessions, want 1", n)
+	}
+}
+
+func testSweep(t *testing.T, store sessionStore) {
+	m := newTestSessions(store)
+	now := time.Now()
+	sessions := map[string]*session{
+		"fresh":   {ID: "fresh", Created: now, LastSeen: now},
+		"idle":    {ID: "idle", Created: now.Add(-2 * time.Hour), LastSeen: now.Add(-2 * time.Hour)},
+		"too-old": {ID: "too-old", Created: now.Add(-48 * time.Hour), LastSeen: now},
+	}
+	for _, s := range sessions {
+		s.Values = map[string]string{}
+		if err := store.Save(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m.sweep()
+
+	for id := range sessions {
+		_, err := store.Load(id)
+		if keep := id == "fresh"; keep != (err == nil) {
+			t.Errorf("after sweep, Load(%q) = %v; want kept = %v", id, err, keep)
+		}
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	testSweep(t, newMemoryStore())
+}
+
+func TestFileStoreSweep(t *testing.T) {
+	dir := t.TempDir()
+	store, err := newFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A file that is not a session, as a crash might leave behind.
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	testSweep(t, store)
+	if _, err := os.Stat(filepath.Join(dir, "broken.json")); !os.IsNotExist(err) {
+		t.Errorf("sweep left broken.json behind: %v", err)
+	}
+}
+
+// testRenewID logs in through a session that already exists, as an
+// attacker who planted the id would have it, and checks that the id
+// stops working while the new one carries the login.
+func testRenewID(t *testing.T, store sessionStore) {
+	m := newTestSessions(store)
+	a := &app{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/visit", func(w http.ResponseWriter, r *http.Request) {
+		sessionFrom(r).Set("visited", "yes")
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		renewID(w, r)
+		sessionFrom(r).Set("name", "Ada")
+	})
+	mux.HandleFunc("/hello", a.helloHandler)
+	h := m.middleware(mux)
+
+	get := func(path string, c *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if c != nil {
+			req.AddCookie(c)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	cookieOf := func(rec *httptest.ResponseRecorder) *http.Cookie {
+		t.Helper()
+		cookies := rec.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != sessionCookie {
+			t.Fatalf("got cookies %v, want one %q cookie", cookies, sessionCookie)
+		}
+		return cookies[0]
+	}
+
+	before := cookieOf(get("/visit", nil))
+	oldID, _ := m.verify(before.Value)
+	after := cookieOf(get("/login", before))
+	newID, _ := m.verify(after.Value)
+	if newID == oldID {
+		t.Fatalf("login kept the session id %s", oldID)
+	}
+	if _, err := store.Load(oldID); !errors.Is(err, errNoSession) {
+		t.Errorf("old session id still loads: %v", err)
+	}
+
+	if got := get("/hello", after).Body.String(); got != "HELLO Ada, welcome back" {
+		t.Errorf("with the new cookie /hello = %q", got)
+	}
+	if got := get("/hello", before).Body.String(); got != "HELLO WORLD" {
+		t.Errorf("with the old cookie /hello = %q, want HELLO WORLD", got)
+	}
+	if got := get("/hello", nil).Body.String(); got != "HELLO WORLD" {
+		t.Errorf("without a cookie /hello = %q", got)
+	}
+}
+
+func TestMemoryStoreRenewID(t *testing.T) {
+	testRenewID(t, newMemoryStore())
+}
+
+func TestFileStoreRenewID(t *testing.T) {
+	store, err := newFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRenewID(t, store)
+}
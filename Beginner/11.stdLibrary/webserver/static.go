@@ -0,0 +1,177 @@
+package main
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// embeddedFiles holds the templates and static assets shipped inside the
+// binary, so the server can run from any directory when started with -embed.
+//
+//go:embed templates static
+var embeddedFiles embed.FS
+
+// hiddenExts lists file extensions that are never served, even if they
+// live inside the static root.
+var hiddenExts = map[string]bool{
+	".go":  true,
+	".mod": true,
+	".sum": true,
+}
+
+// staticHandler serves files from fsys.
+// Because fsys is an fs.FS, paths can never escape its root; on top of that
+// dotfiles and source files are hidden and directory listings are disabled.
+type staticHandler struct {
+	fsys         fs.FS
+	cacheControl string
+}
+
+// newStaticHandler returns a handler serving the static root from c.
+func newStaticHandler(c config) (http.Handler, error) {
+	var fsys fs.FS = os.DirFS(c.StaticRoot)
+	if c.Embed {
+		sub, err := fs.Sub(embeddedFiles, "static")
+		if err != nil {
+			return nil, err
+		}
+		fsys = sub
+	}
+	return &staticHandler{fsys: fsys, cacheControl: "public, max-age=300"}, nil
+}
+
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method is not supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "index.html"
+	}
+	if !allowed(name) {
+		http.NotFound(w, r)
+		return
+	}
+
+	info, err := fs.Stat(h.fsys, name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if info.IsDir() {
+		// A directory is only served through its index.html; there are no listings.
+		name = path.Join(name, "index.html")
+		if info, err = fs.Stat(h.fsys, name); err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	// Serve the precompressed variant if the client accepts gzip and one exists.
+	servedName := name
+	if acceptsGzip(r) {
+		if gzInfo, err := fs.Stat(h.fsys, name+".gz"); err == nil && !gzInfo.IsDir() {
+			servedName = name + ".gz"
+			info = gzInfo
+			w.Header().Set("Content-Encoding", "gzip")
+		}
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	content, err := h.fsys.Open(servedName)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer content.Close()
+	rs, ok := content.(io.ReadSeeker)
+	if !ok {
+		http.Error(w, "500 internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	etag, err := fileETag(info, rs)
+	if err != nil {
+		http.Error(w, "500 internal server error", http.StatusInternalServerError)
+		return
+	}
+	if ctype := mime.TypeByExtension(path.Ext(name)); ctype != "" {
+		w.Header().Set("Content-Type", ctype)
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", h.cacheControl)
+
+	// ServeContent takes care of Last-Modified, If-None-Match,
+	// If-Modified-Since and Range requests for us.
+	http.ServeContent(w, r, name, info.ModTime(), rs)
+}
+
+// allowed reports whether name may be served: no path element may be
+// a dotfile and the extension must not be a hidden one.
+func allowed(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return !hiddenExts[path.Ext(name)]
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding header
+// accepts gzip, by name or through "*". A quality of zero, as in
+// "gzip;q=0", refuses it.
+func acceptsGzip(r *http.Request) bool {
+	gzipQ, anyQ := -1.0, -1.0
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		enc, params, _ := strings.Cut(enc, ";")
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			if k, v, ok := strings.Cut(p, "="); ok && strings.TrimSpace(k) == "q" {
+				var err error
+				if q, err = strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+					q = 0
+				}
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(enc)) {
+		case "gzip", "x-gzip":
+			gzipQ = q
+		case "*":
+			anyQ = q
+		}
+	}
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return anyQ > 0
+}
+
+// fileETag builds a strong ETag for a file.
+// Files on disk use their size and modification time; embedded files have
+// no modification time, so their content is hashed instead.
+func fileETag(info fs.FileInfo, rs io.ReadSeeker) (string, error) {
+	if !info.ModTime().IsZero() {
+		return fmt.Sprintf(`"%x-%x"`, info.Size(), info.ModTime().UnixNano()), nil
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, rs); err != nil {
+		return "", err
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`, nil
+}
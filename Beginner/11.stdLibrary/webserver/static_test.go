@@ -0,0 +1,150 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func newTestStatic() *staticHandler {
+	return &staticHandler{
+		fsys: fstest.MapFS{
+			"index.html":        {Data: []byte("<h1>home</h1>")},
+			"style.css":         {Data: []byte("body{}"), ModTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+			"app.js":            {Data: []byte("plain js")},
+			"app.js.gz":         {Data: []byte("gzipped js")},
+			".env":              {Data: []byte("SECRET=1")},
+			".git/config":       {Data: []byte("[core]")},
+			"img/.hidden.png":   {Data: []byte("png")},
+			"main.go":           {Data: []byte("package main")},
+			"docs/index.html":   {Data: []byte("docs")},
+			"assets/logo.svg":   {Data: []byte("<svg/>")},
+			"assets/extra.txt":  {Data: []byte("extra")},
+			"nested/deep/a.txt": {Data: []byte("a")},
+		},
+		cacheControl: "public, max-age=300",
+	}
+}
+
+func serveStatic(h http.Handler, method, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestStaticPaths(t *testing.T) {
+	h := newTestStatic()
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/", 200, "<h1>home</h1>"},
+		{"/style.css", 200, "body{}"},
+		{"/docs/", 200, "docs"}, // a directory's index.html
+		{"/docs", 200, "docs"},
+		{"/assets/", 404, ""}, // no listing without an index.html
+		{"/nested", 404, ""},
+		{"/.env", 404, ""}, // dotfiles and files in dot directories
+		{"/.git/config", 404, ""},
+		{"/img/.hidden.png", 404, ""},
+		{"/main.go", 404, ""}, // source files
+		{"/../static.go", 404, ""},
+		{"/assets/../../config.go", 404, ""}, // cannot leave the root
+		{"/missing.txt", 404, ""},
+	}
+	for _, tt := range tests {
+		rec := serveStatic(h, "GET", tt.path, nil)
+		if rec.Code != tt.code || (tt.code == 200 && rec.Body.String() != tt.body) {
+			t.Errorf("GET %s = %d %q, want %d %q", tt.path, rec.Code, rec.Body.String(), tt.code, tt.body)
+		}
+	}
+	if rec := serveStatic(h, "POST", "/style.css", nil); rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("POST = %d, Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
+
+func TestStaticGzip(t *testing.T) {
+	h := newTestStatic()
+	tests := []struct {
+		accept string
+		gzip   bool
+	}{
+		{"", false},
+		{"gzip", true},
+		{"deflate, gzip;q=0.8", true},
+		{"gzip;q=0", false},
+		{"gzip; q=0.0, deflate", false},
+		{"GZIP", true},
+		{"*", true},
+		{"*;q=0", false},
+		{"gzip;q=0, *", false}, // refused by name, whatever * says
+		{"br", false},
+	}
+	for _, tt := range tests {
+		rec := serveStatic(h, "GET", "/app.js", map[string]string{"Accept-Encoding": tt.accept})
+		want, enc := "plain js", ""
+		if tt.gzip {
+			want, enc = "gzipped js", "gzip"
+		}
+		if rec.Body.String() != want || rec.Header().Get("Content-Encoding") != enc {
+			t.Errorf("Accept-Encoding %q: got %q with Content-Encoding %q, want %q with %q",
+				tt.accept, rec.Body.String(), rec.Header().Get("Content-Encoding"), want, enc)
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, "javascript") {
+			t.Errorf("Accept-Encoding %q: Content-Type %q, want that of the uncompressed file", tt.accept, ct)
+		}
+		if rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("Accept-Encoding %q: Vary %q", tt.accept, rec.Header().Get("Vary"))
+		}
+	}
+	// Without a .gz file the plain file is served.
+	if rec := serveStatic(h, "GET", "/style.css", map[string]string{"Accept-Encoding": "gzip"}); rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("style.css has no .gz but was served with Content-Encoding %q", rec.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestStaticETag(t *testing.T) {
+	h := newTestStatic()
+	for _, path := range []string{"/style.css", "/index.html"} { // with and without a modification time
+		rec := serveStatic(h, "GET", path, nil)
+		etag := rec.Header().Get("ETag")
+		if rec.Code != 200 || !strings.HasPrefix(etag, `"`) || len(etag) < 4 {
+			t.Fatalf("GET %s: %d, ETag %q", path, rec.Code, etag)
+		}
+		if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=300" {
+			t.Errorf("GET %s: Cache-Control %q", path, cc)
+		}
+		if again := serveStatic(h, "GET", path, nil).Header().Get("ETag"); again != etag {
+			t.Errorf("GET %s: ETag changed from %s to %s", path, etag, again)
+		}
+		if rec := serveStatic(h, "GET", path, map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("GET %s with If-None-Match %s = %d, want 304 and no body", path, etag, rec.Code)
+		}
+		if rec := serveStatic(h, "GET", path, map[string]string{"If-None-Match": `"other"`}); rec.Code != 200 {
+			t.Errorf("GET %s with a stale If-None-Match = %d, want 200", path, rec.Code)
+		}
+	}
+	plain := serveStatic(h, "GET", "/app.js", nil).Header().Get("ETag")
+	gz := serveStatic(h, "GET", "/app.js", map[string]string{"Accept-Encoding": "gzip"}).Header().Get("ETag")
+	if plain == gz {
+		t.Errorf("the plain and gzipped app.js share the ETag %s", plain)
+	}
+}
+
+func TestStaticEmbedded(t *testing.T) {
+	h, err := newStaticHandler(config{Embed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := serveStatic(h, "GET", "/style.css", nil); rec.Code != 200 || rec.Header().Get("ETag") == "" {
+		t.Errorf("embedded style.css: %d, ETag %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
@@ -6,10 +6,10 @@ package main
 
 import "fmt"
 
-func revSlice(x []int, j int) []int {
+func revSlice(x []int) []int {
 	result := make([]int, len(x))
 	z := len(x)
-	for i := 0; i < j; i++ {
+	for i := 0; i < len(x); i++ {
 		z = z - 1
 		result[i] = x[z]
 
@@ -21,7 +21,7 @@ func revSlice(x []int, j int) []int {
 func main() {
 	x := []int{1, 2, 3, 4} //input slice
 
-	y := revSlice(x, len(x)) //output slice
+	y := revSlice(x) //output slice
 
 	fmt.Println("reversed slice is ", y)
 
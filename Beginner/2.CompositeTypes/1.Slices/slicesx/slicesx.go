@@ -0,0 +1,156 @@
+// Package slicesx has generic versions of the slice problems in
+// Questions.txt: reversing, finding the smallest and largest element,
+// removing duplicates, merging, finding common elements, the largest
+// subarray sum and rotating.
+//
+// None of the functions modify their arguments; those returning a slice
+// return a new one that does not share a backing array with the input.
+//
+// Vec is a list type that shares storage between views like slices do,
+// but copies it on write instead of letting one view change another.
+package slicesx
+
+import "errors"
+
+// Ordered is the set of types that support < and >.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// Number is the set of types that support + as well as <.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// ErrEmpty is returned by functions that need at least one element.
+var ErrEmpty = errors.New("slicesx: empty slice")
+
+// Reverse returns the elements of s in reverse order.
+func Reverse[S ~[]E, E any](s S) S {
+	r := make(S, len(s))
+	for i, v := range s {
+		r[len(s)-1-i] = v
+	}
+	return r
+}
+
+// Min returns the smallest element of s, or ErrEmpty if s is empty.
+func Min[E Ordered](s []E) (E, error) {
+	return best(s, func(a, b E) bool { return a < b })
+}
+
+// Max returns the largest element of s, or ErrEmpty if s is empty.
+func Max[E Ordered](s []E) (E, error) {
+	return best(s, func(a, b E) bool { return a > b })
+}
+
+// best returns the first element of s that no other is better than.
+func best[E any](s []E, better func(a, b E) bool) (E, error) {
+	if len(s) == 0 {
+		var zero E
+		return zero, ErrEmpty
+	}
+	result := s[0]
+	for _, v := range s[1:] {
+		if better(v, result) {
+			result = v
+		}
+	}
+	return result, nil
+}
+
+// Unique returns the elements of s without duplicates, keeping the first
+// of each in its original order.
+func Unique[S ~[]E, E comparable](s S) S {
+	seen := make(map[E]bool, len(s))
+	r := make(S, 0, len(s))
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// MergeSorted merges a and b, which must both be sorted in ascending
+// order, into a single sorted slice. Equal elements from a come before
+// those from b. To merge unsorted slices, sort them first, or append them
+// and sort the result.
+func MergeSorted[S ~[]E, E Ordered](a, b S) S {
+	r := make(S, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if b[j] < a[i] {
+			r = append(r, b[j])
+			j++
+		} else {
+			r = append(r, a[i])
+			i++
+		}
+	}
+	r = append(r, a[i:]...)
+	return append(r, b[j:]...)
+}
+
+// Intersect returns the elements found in both a and b, each once, in
+// the order they first appear in a.
+func Intersect[S ~[]E, E comparable](a, b S) S {
+	inB := make(map[E]bool, len(b))
+	for _, v := range b {
+		inB[v] = true
+	}
+	r := S{}
+	for _, v := range a {
+		if inB[v] {
+			r = append(r, v)
+			delete(inB, v)
+		}
+	}
+	return r
+}
+
+// MaxSubarray finds the contiguous, non-empty run of s with the largest
+// sum, using Kadane's algorithm. It returns the sum and the run as
+// s[start:end]. When several runs have the same sum, the one starting
+// first, and of those the shortest, is returned. An empty s returns
+// ErrEmpty.
+func MaxSubarray[E Number](s []E) (sum E, start, end int, err error) {
+	if len(s) == 0 {
+		return sum, 0, 0, ErrEmpty
+	}
+	sum, start, end = s[0], 0, 1
+	cur, curStart := s[0], 0 // best sum of a run ending at i, and its start
+	for i := 1; i < len(s); i++ {
+		if cur < 0 {
+			cur, curStart = s[i], i
+		} else {
+			cur += s[i]
+		}
+		if cur > sum {
+			sum, start, end = cur, curStart, i+1
+		}
+	}
+	return sum, start, end, nil
+}
+
+// Rotate returns s rotated right by k positions: rotating [1 2 3 4 5] by
+// 2 gives [4 5 1 2 3]. A negative k rotates left, and k may be larger
+// than len(s).
+func Rotate[S ~[]E, E any](s S, k int) S {
+	r := make(S, len(s))
+	if len(s) == 0 {
+		return r
+	}
+	k %= len(s)
+	if k < 0 {
+		k += len(s)
+	}
+	n := copy(r, s[len(s)-k:])
+	copy(r[n:], s[:len(s)-k])
+	return r
+}
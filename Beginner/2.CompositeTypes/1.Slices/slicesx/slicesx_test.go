@@ -0,0 +1,290 @@
+package slicesx
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestReverse(t *testing.T) {
+	tests := []struct{ in, want []int }{
+		{nil, []int{}},
+		{[]int{1}, []int{1}},
+		{[]int{1, 2, 3, 4}, []int{4, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		if got := Reverse(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Reverse(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	tests := []struct {
+		in       []float64
+		min, max float64
+	}{
+		{[]float64{3}, 3, 3},
+		{[]float64{3, -1, 7, 7, 0}, -1, 7},
+	}
+	for _, tt := range tests {
+		if got, err := Min(tt.in); err != nil || got != tt.min {
+			t.Errorf("Min(%v) = %v, %v; want %v", tt.in, got, err, tt.min)
+		}
+		if got, err := Max(tt.in); err != nil || got != tt.max {
+			t.Errorf("Max(%v) = %v, %v; want %v", tt.in, got, err, tt.max)
+		}
+	}
+	if _, err := Min([]string{}); err != ErrEmpty {
+		t.Errorf("Min of empty: got %v, want ErrEmpty", err)
+	}
+	if _, err := Max[int](nil); err != ErrEmpty {
+		t.Errorf("Max of nil: got %v, want ErrEmpty", err)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	tests := []struct{ in, want []string }{
+		{nil, []string{}},
+		{[]string{"a", "b", "a", "c", "b"}, []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		if got := Unique(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Unique(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	tests := []struct{ a, b, want []int }{
+		{nil, nil, []int{}},
+		{[]int{1, 3, 5}, nil, []int{1, 3, 5}},
+		{[]int{1, 3, 5}, []int{2, 3, 6, 7}, []int{1, 2, 3, 3, 5, 6, 7}},
+	}
+	for _, tt := range tests {
+		if got := MergeSorted(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MergeSorted(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	tests := []struct{ a, b, want []int }{
+		{nil, []int{1}, []int{}},
+		{[]int{4, 1, 2, 1, 3}, []int{3, 1, 1, 5}, []int{1, 3}},
+	}
+	for _, tt := range tests {
+		if got := Intersect(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Intersect(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMaxSubarray(t *testing.T) {
+	tests := []struct {
+		in              []int
+		sum, start, end int
+	}{
+		{[]int{-2, 1, -3, 4, -1, 2, 1, -5, 4}, 6, 3, 7},
+		{[]int{-3, -1, -2}, -1, 1, 2},
+		{[]int{5, 0, -1}, 5, 0, 1}, // the shortest of the runs starting first
+		{[]int{0, 5}, 5, 0, 2},     // the run starting first
+	}
+	for _, tt := range tests {
+		sum, start, end, err := MaxSubarray(tt.in)
+		if err != nil || sum != tt.sum || start != tt.start || end != tt.end {
+			t.Errorf("MaxSubarray(%v) = %d, [%d:%d], %v; want %d, [%d:%d]",
+				tt.in, sum, start, end, err, tt.sum, tt.start, tt.end)
+		}
+	}
+	if _, _, _, err := MaxSubarray([]int{}); err != ErrEmpty {
+		t.Errorf("MaxSubarray of empty: got %v, want ErrEmpty", err)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	in := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		k    int
+		want []int
+	}{
+		{0, []int{1, 2, 3, 4, 5}},
+		{2, []int{4, 5, 1, 2, 3}},
+		{-1, []int{2, 3, 4, 5, 1}},
+		{12, []int{4, 5, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		if got := Rotate(in, tt.k); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Rotate(%v, %d) = %v, want %v", in, tt.k, got, tt.want)
+		}
+	}
+	if got := Rotate([]int{}, 3); len(got) != 0 {
+		t.Errorf("Rotate of empty = %v", got)
+	}
+}
+
+func TestNoAliasing(t *testing.T) {
+	// None of the results may share a backing array with an argument.
+	in := []int{3, 1, 2}
+	for name, got := range map[string][]int{
+		"Reverse":     Reverse(in),
+		"Unique":      Unique(in),
+		"MergeSorted": MergeSorted(in, nil),
+		"Intersect":   Intersect(in, in),
+		"Rotate":      Rotate(in, 0),
+	} {
+		got[0] = 99
+		if in[0] != 3 {
+			t.Fatalf("changing the result of %s changed its argument", name)
+		}
+	}
+}
+
+// toInts turns fuzz input into small signed integers, so that sums of
+// them cannot overflow.
+func toInts(data []byte) []int {
+	s := make([]int, len(data))
+	for i, b := range data {
+		s[i] = int(int8(b))
+	}
+	return s
+}
+
+func FuzzReverse(f *testing.F) {
+	f.Add([]byte{1, 2, 3})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		got := Reverse(data)
+		for i := range data {
+			if got[i] != data[len(data)-1-i] {
+				t.Fatalf("Reverse(%v) = %v", data, got)
+			}
+		}
+		if !reflect.DeepEqual(Reverse(got), append([]byte{}, data...)) {
+			t.Fatalf("Reverse(Reverse(%v)) = %v", data, Reverse(got))
+		}
+	})
+}
+
+func FuzzMinMax(f *testing.F) {
+	f.Add([]byte{5, 200, 3})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s := toInts(data)
+		min, minErr := Min(s)
+		max, maxErr := Max(s)
+		if len(s) == 0 {
+			if minErr != ErrEmpty || maxErr != ErrEmpty {
+				t.Fatalf("empty input: %v, %v", minErr, maxErr)
+			}
+			return
+		}
+		sorted := append([]int{}, s...)
+		sort.Ints(sorted)
+		if min != sorted[0] || max != sorted[len(sorted)-1] {
+			t.Fatalf("Min, Max of %v = %d, %d; want %d, %d", s, min, max, sorted[0], sorted[len(sorted)-1])
+		}
+	})
+}
+
+func FuzzUnique(f *testing.F) {
+	f.Add([]byte("abracadabra"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Naive: keep an element if it does not appear earlier.
+		want := []byte{}
+		for i, b := range data {
+			dup := false
+			for _, c := range data[:i] {
+				dup = dup || c == b
+			}
+			if !dup {
+				want = append(want, b)
+			}
+		}
+		if got := Unique(data); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Unique(%v) = %v, want %v", data, got, want)
+		}
+	})
+}
+
+func FuzzMergeSorted(f *testing.F) {
+	f.Add([]byte{1, 4, 9}, []byte{2, 4})
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+		sort.Slice(b, func(i, j int) bool { return b[i] < b[j] })
+		want := append(append([]byte{}, a...), b...)
+		sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+		if got := MergeSorted(a, b); !reflect.DeepEqual(got, want) {
+			t.Fatalf("MergeSorted(%v, %v) = %v, want %v", a, b, got, want)
+		}
+	})
+}
+
+func FuzzIntersect(f *testing.F) {
+	f.Add([]byte("hello"), []byte("world"))
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		want := []byte{}
+		for _, x := range a {
+			inB, seen := false, false
+			for _, y := range b {
+				inB = inB || x == y
+			}
+			for _, y := range want {
+				seen = seen || x == y
+			}
+			if inB && !seen {
+				want = append(want, x)
+			}
+		}
+		if got := Intersect(a, b); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Intersect(%v, %v) = %v, want %v", a, b, got, want)
+		}
+	})
+}
+
+func FuzzMaxSubarray(f *testing.F) {
+	f.Add([]byte{0xfe, 1, 0xfd, 4, 0xff, 2, 1, 0xfb, 4})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s := toInts(data)
+		sum, start, end, err := MaxSubarray(s)
+		if len(s) == 0 {
+			if err != ErrEmpty {
+				t.Fatalf("empty input: %v", err)
+			}
+			return
+		}
+		// Naive: try every run, earliest start first and then shortest,
+		// keeping only strictly larger sums, which is the documented tie
+		// break.
+		wantSum, wantStart, wantEnd := s[0], 0, 1
+		for i := range s {
+			run := 0
+			for j := i; j < len(s); j++ {
+				run += s[j]
+				if run > wantSum {
+					wantSum, wantStart, wantEnd = run, i, j+1
+				}
+			}
+		}
+		if sum != wantSum || start != wantStart || end != wantEnd {
+			t.Fatalf("MaxSubarray(%v) = %d [%d:%d], want %d [%d:%d]", s, sum, start, end, wantSum, wantStart, wantEnd)
+		}
+	})
+}
+
+func FuzzRotate(f *testing.F) {
+	f.Add([]byte{1, 2, 3, 4, 5}, 2)
+	f.Fuzz(func(t *testing.T, data []byte, k int) {
+		// Naive: move the last element to the front, or the first to the
+		// back, one step at a time. Keep the number of steps small.
+		steps := k % 1000
+		want := append([]byte{}, data...)
+		for i := 0; len(want) > 0 && i < steps; i++ {
+			want = append([]byte{want[len(want)-1]}, want[:len(want)-1]...)
+		}
+		for i := 0; len(want) > 0 && i > steps; i-- {
+			want = append(want[1:], want[0])
+		}
+		if got := Rotate(data, steps); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Rotate(%v, %d) = %v, want %v", data, steps, got, want)
+		}
+	})
+}
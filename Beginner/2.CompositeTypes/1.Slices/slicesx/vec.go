@@ -0,0 +1,156 @@
+package slicesx
+
+import "fmt"
+
+// Vec is a list of values that, unlike a slice, never shares storage by
+// accident.
+//
+// With slices, y := x[:2] shares x's backing array: setting y[0] changes
+// x[0], and append(y, 9) overwrites x[2]. Vec.Slice also shares storage,
+// so taking a view is cheap, but whichever of the two Vecs writes first
+// gets its own copy of the storage it writes to, so neither can see the
+// other's changes.
+//
+// Storage is kept in fixed size chunks, and only the chunk being written
+// is copied, so changing one element of a large shared Vec is cheap.
+//
+// A Vec must not be copied by assignment, since the copy would share
+// storage without knowing it; use Slice or Clone. The zero value is an
+// empty Vec ready to use. A Vec is not safe for concurrent use.
+type Vec[T any] struct {
+	chunks []*chunk[T]
+	off    int // index in chunks[0] of the first element
+	n      int
+	view   bool // made by Slice
+	debug  bool
+}
+
+// chunkSize is the number of elements in a chunk.
+const chunkSize = 64
+
+// chunk is a piece of storage and the number of Vecs using it. The count
+// is only ever lowered by a Vec replacing the chunk with its own copy, so
+// a chunk shared with a Vec that is no longer used is still copied on the
+// next write; this costs a copy, never correctness.
+type chunk[T any] struct {
+	items [chunkSize]T
+	refs  int
+}
+
+// VecOf returns a Vec holding a copy of items.
+func VecOf[T any](items ...T) *Vec[T] {
+	v := &Vec[T]{}
+	v.Append(items...)
+	return v
+}
+
+// Len returns the number of elements in v.
+func (v *Vec[T]) Len() int { return v.n }
+
+// SetDebug turns debug mode on or off for v and the views later taken
+// from it. In debug mode, a view that writes to storage it shares panics
+// instead of quietly copying it, to find code that relies on a write
+// through a view being seen elsewhere, as it would be with slices.
+func (v *Vec[T]) SetDebug(on bool) { v.debug = on }
+
+// locate returns the chunk and index within it of element i.
+func (v *Vec[T]) locate(i int) (int, int) {
+	if i < 0 || i >= v.n {
+		panic(fmt.Sprintf("slicesx: index %d out of range [0:%d]", i, v.n))
+	}
+	p := v.off + i
+	return p / chunkSize, p % chunkSize
+}
+
+// At returns element i.
+func (v *Vec[T]) At(i int) T {
+	c, j := v.locate(i)
+	return v.chunks[c].items[j]
+}
+
+// Set sets element i to x.
+func (v *Vec[T]) Set(i int, x T) {
+	c, j := v.locate(i)
+	v.own(c)
+	v.chunks[c].items[j] = x
+}
+
+// Append adds xs to the end of v.
+func (v *Vec[T]) Append(xs ...T) {
+	for _, x := range xs {
+		p := v.off + v.n
+		c, j := p/chunkSize, p%chunkSize
+		if c == len(v.chunks) {
+			v.chunks = append(v.chunks, &chunk[T]{refs: 1})
+		} else {
+			// There is room after the last element, but it may be
+			// storage another Vec can see.
+			v.own(c)
+		}
+		v.chunks[c].items[j] = x
+		v.n++
+	}
+}
+
+// own makes sure chunk c is used by v alone, copying it if it is shared.
+func (v *Vec[T]) own(c int) {
+	old := v.chunks[c]
+	if old.refs == 1 {
+		return
+	}
+	if v.debug && v.view {
+		panic("slicesx: write through a view to shared storage; Clone the view to write to it")
+	}
+	fresh := &chunk[T]{items: old.items, refs: 1}
+	old.refs--
+	// v.chunks itself may be shared with the Vec this one was sliced
+	// from, so replace the chunk in a copy of it.
+	chunks := make([]*chunk[T], len(v.chunks))
+	copy(chunks, v.chunks)
+	chunks[c] = fresh
+	v.chunks = chunks
+}
+
+// Slice returns a view of elements i to j-1 of v, which shares v's
+// storage until either of them is changed. It panics, like slicing a
+// slice, unless 0 <= i <= j <= v.Len().
+func (v *Vec[T]) Slice(i, j int) *Vec[T] {
+	if i < 0 || j < i || j > v.n {
+		panic(fmt.Sprintf("slicesx: slice bounds [%d:%d] out of range [0:%d]", i, j, v.n))
+	}
+	w := &Vec[T]{n: j - i, view: true, debug: v.debug}
+	if w.n == 0 {
+		return w
+	}
+	first := (v.off + i) / chunkSize
+	last := (v.off + j - 1) / chunkSize
+	w.off = (v.off + i) % chunkSize
+	w.chunks = v.chunks[first : last+1 : last+1]
+	for _, c := range w.chunks {
+		c.refs++
+	}
+	return w
+}
+
+// Clone returns a copy of v with storage of its own. A clone of a view is
+// not a view.
+func (v *Vec[T]) Clone() *Vec[T] {
+	w := &Vec[T]{debug: v.debug}
+	for i := 0; i < v.n; i++ {
+		w.Append(v.At(i))
+	}
+	return w
+}
+
+// Items returns the elements of v in a new slice.
+func (v *Vec[T]) Items() []T {
+	items := make([]T, v.n)
+	for i := range items {
+		items[i] = v.At(i)
+	}
+	return items
+}
+
+func (v *Vec[T]) String() string {
+	return fmt.Sprint(v.Items())
+}
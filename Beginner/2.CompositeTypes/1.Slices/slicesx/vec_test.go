@@ -0,0 +1,155 @@
+package slicesx
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func seq(n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}
+
+// TestSliceAliasing shows the slice behaviour Vec exists to avoid, so
+// that the Vec tests below have something to be compared with.
+func TestSliceAliasing(t *testing.T) {
+	x := []int{0, 1, 2}
+	y := x[:2]
+	y[0] = 10
+	_ = append(y, 9)
+	if want := []int{10, 1, 9}; !reflect.DeepEqual(x, want) {
+		t.Fatalf("x = %v, want %v: slices no longer alias as the Vec docs say", x, want)
+	}
+}
+
+func TestVecViewSet(t *testing.T) {
+	x := VecOf(0, 1, 2)
+	y := x.Slice(0, 2)
+	y.Set(0, 10)
+	if got := x.Items(); !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("setting the view changed the Vec it came from: %v", got)
+	}
+	x.Set(1, 11)
+	if got := y.Items(); !reflect.DeepEqual(got, []int{10, 1}) {
+		t.Errorf("setting the Vec changed the view taken from it: %v", got)
+	}
+}
+
+func TestVecViewAppend(t *testing.T) {
+	// The view ends in the middle of a chunk, so its append goes to
+	// storage the original still uses for x[2].
+	x := VecOf(0, 1, 2)
+	y := x.Slice(0, 2)
+	y.Append(9)
+	if got := x.Items(); !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("appending to the view overwrote the Vec it came from: %v", got)
+	}
+	if got := y.Items(); !reflect.DeepEqual(got, []int{0, 1, 9}) {
+		t.Errorf("view = %v, want [0 1 9]", got)
+	}
+
+	// The same the other way round: appending to the original must not
+	// show up in a view that has room after it.
+	z := x.Slice(1, 1)
+	x.Append(3)
+	z.Append(7)
+	if got := x.Items(); !reflect.DeepEqual(got, []int{0, 1, 2, 3}) {
+		t.Errorf("x = %v, want [0 1 2 3]", got)
+	}
+	if got := z.Items(); !reflect.DeepEqual(got, []int{7}) {
+		t.Errorf("z = %v, want [7]", got)
+	}
+}
+
+func TestVecSiblingViews(t *testing.T) {
+	// Two views across a chunk boundary, and a view of a view.
+	x := VecOf(seq(3 * chunkSize)...)
+	a := x.Slice(chunkSize-2, chunkSize+2)
+	b := x.Slice(chunkSize-1, 2*chunkSize)
+	c := b.Slice(0, 2)
+	a.Set(1, -1)
+	b.Set(1, -2)
+	c.Set(0, -3)
+
+	if got := x.Items(); !reflect.DeepEqual(got, seq(3*chunkSize)) {
+		t.Errorf("writes through views changed the original")
+	}
+	if got, want := a.Items(), []int{chunkSize - 2, -1, chunkSize, chunkSize + 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("a = %v, want %v", got, want)
+	}
+	if got, want := b.Items()[:3], []int{chunkSize - 1, -2, chunkSize + 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("b starts %v, want %v", got, want)
+	}
+	if got, want := c.Items(), []int{-3, chunkSize}; !reflect.DeepEqual(got, want) {
+		t.Errorf("c = %v, want %v", got, want)
+	}
+}
+
+func TestVecDebug(t *testing.T) {
+	x := VecOf(1, 2, 3)
+	x.SetDebug(true)
+	y := x.Slice(0, 2)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("writing through a view in debug mode did not panic")
+		}
+	}()
+	// A clone has storage of its own, so writing to it is fine.
+	y.Clone().Set(0, 10)
+	y.Set(0, 10)
+}
+
+func TestVecSliceBounds(t *testing.T) {
+	x := VecOf(1, 2, 3)
+	for _, b := range [][2]int{{-1, 1}, {2, 1}, {0, 4}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Slice(%d, %d) of a Vec of 3 did not panic", b[0], b[1])
+				}
+			}()
+			x.Slice(b[0], b[1])
+		}()
+	}
+	if got := x.Slice(3, 3).Len(); got != 0 {
+		t.Errorf("Slice(3, 3).Len() = %d", got)
+	}
+}
+
+// TestVecModel runs random Set, Append and Slice calls on a family of
+// Vecs and checks each against a plain slice that is copied whenever
+// the Vec is sliced, which is the behaviour Vec promises.
+func TestVecModel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	vecs := []*Vec[int]{VecOf(seq(150)...)}
+	models := [][]int{seq(150)}
+	for step := 0; step < 5000; step++ {
+		k := rng.Intn(len(vecs))
+		v, m := vecs[k], models[k]
+		switch op := rng.Intn(3); {
+		case op == 0 && len(m) > 0:
+			i, x := rng.Intn(len(m)), rng.Int()
+			v.Set(i, x)
+			m[i] = x
+		case op == 1:
+			x := rng.Int()
+			v.Append(x)
+			models[k] = append(m, x)
+		case op == 2 && len(vecs) < 20:
+			i := rng.Intn(len(m) + 1)
+			j := i + rng.Intn(len(m)-i+1)
+			vecs = append(vecs, v.Slice(i, j))
+			models = append(models, append([]int{}, m[i:j]...))
+		}
+		for i, v := range vecs {
+			if got := v.Items(); !reflect.DeepEqual(got, append([]int{}, models[i]...)) {
+				t.Fatalf("step %d: Vec %d = %v, want %v", step, i, got, models[i])
+			}
+		}
+	}
+}
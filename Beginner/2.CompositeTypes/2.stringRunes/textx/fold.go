@@ -0,0 +1,76 @@
+package textx
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Fold returns s case folded, for comparing strings without regard to
+// case. It goes further than strings.EqualFold, which only matches single
+// runes: the German ß folds to "ss" and ligatures such as ﬁ to their
+// letters, so Fold("STRASSE") == Fold("straße").
+//
+// Fold is not the full case folding of Unicode's CaseFolding.txt. Runes
+// are folded one at a time through the unicode package's case tables,
+// and only the multi-rune folds in fullFolds are applied, so rarer ones,
+// such as Greek letters with a iota subscript or Armenian ligatures, and
+// the Turkic dotted and dotless i, fold as they would with simple rune
+// mapping. Programs that need the full folding should use cases.Fold
+// from golang.org/x/text, which this package does without so that it
+// needs nothing outside the standard library.
+//
+// Fold does not normalize either: "é" written as one rune and as 'e'
+// plus a combining accent fold differently. Invalid bytes are kept as
+// they are.
+func Fold(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		i += size
+		if f, ok := fullFolds[r]; ok {
+			b.WriteString(f)
+			continue
+		}
+		// Going through upper case first maps every case variant of a
+		// letter, such as final sigma ς and the Kelvin sign, to the same
+		// lower case rune.
+		b.WriteRune(unicode.ToLower(unicode.ToUpper(r)))
+	}
+	return b.String()
+}
+
+// fullFolds are the common runes whose case folding is more than one
+// rune, from Unicode's CaseFolding.txt.
+var fullFolds = map[rune]string{
+	'\u00df': "ss",      // ß
+	'\u1e9e': "ss",      // ẞ
+	'\u0130': "i\u0307", // İ
+	'\u0149': "\u02bcn", // ŉ
+	'\u01f0': "j\u030c", // ǰ
+	'\ufb00': "ff",      // ﬀ
+	'\ufb01': "fi",      // ﬁ
+	'\ufb02': "fl",      // ﬂ
+	'\ufb03': "ffi",     // ﬃ
+	'\ufb04': "ffl",     // ﬄ
+	'\ufb05': "st",      // ﬅ
+	'\ufb06': "st",      // ﬆ
+}
+
+// EqualFold reports whether a and b are equal after Fold.
+func EqualFold(a, b string) bool {
+	return Fold(a) == Fold(b)
+}
+
+// CompareFold compares a and b after Fold, returning -1, 0 or 1 like
+// strings.Compare. It orders by code point, not by any language's
+// alphabet.
+func CompareFold(a, b string) int {
+	return strings.Compare(Fold(a), Fold(b))
+}
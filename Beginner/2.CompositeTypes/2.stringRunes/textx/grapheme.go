@@ -0,0 +1,124 @@
+// Package textx works on strings as people see them rather than as bytes
+// or runes.
+//
+// A string is a sequence of bytes, usually UTF-8, and a rune is one
+// Unicode code point, but what a reader sees as one character may be
+// several runes: "é" can be 'e' followed by a combining accent, and a
+// family emoji is several emoji joined by zero width joiners. Such a
+// user-perceived character is a grapheme cluster. Reversing or cutting a
+// string by runes splits them; the functions here do not.
+//
+// Invalid UTF-8 is allowed everywhere: each byte that is not part of a
+// valid encoding is treated as a character of its own and kept as it is.
+package textx
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Graphemes splits s into grapheme clusters.
+func Graphemes(s string) []string {
+	var gs []string
+	for len(s) > 0 {
+		n := nextGrapheme(s)
+		gs = append(gs, s[:n])
+		s = s[n:]
+	}
+	return gs
+}
+
+// GraphemeCount returns the number of grapheme clusters in s.
+func GraphemeCount(s string) int {
+	n := 0
+	for len(s) > 0 {
+		s = s[nextGrapheme(s):]
+		n++
+	}
+	return n
+}
+
+// nextGrapheme returns the length in bytes of the grapheme cluster at
+// the start of s, which must not be empty.
+func nextGrapheme(s string) int {
+	var b GraphemeBreaker
+	r, i := utf8.DecodeRuneInString(s)
+	b.Next(r)
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if b.Next(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// GraphemeBreaker finds the grapheme cluster boundaries in a stream of
+// runes, for callers that decode the text themselves, such as a counter
+// reading it a buffer at a time. The zero value is ready to use.
+type GraphemeBreaker struct {
+	prev    rune
+	riCount int // regional indicators in a row, ending with prev
+	started bool
+}
+
+// Next reports whether r, the next rune of the stream, starts a new
+// grapheme cluster. The first rune always does. utf8.RuneError, which
+// decoding gives for invalid bytes, is a cluster of its own.
+func (b *GraphemeBreaker) Next(r rune) bool {
+	brk := !b.started || b.prev == utf8.RuneError || r == utf8.RuneError ||
+		graphemeBreak(b.prev, r, b.riCount)
+	if isRegionalIndicator(r) {
+		b.riCount++
+	} else {
+		b.riCount = 0
+	}
+	b.prev, b.started = r, true
+	return brk
+}
+
+// graphemeBreak reports whether a new grapheme cluster starts at r, given
+// the rune before it and the number of regional indicators directly
+// before r.
+//
+// It implements the common cases of the Unicode grapheme cluster rules
+// (UAX #29): CR LF, combining marks, variation selectors, emoji
+// modifiers, zero width joiner sequences and flag pairs. Rarer rules,
+// such as Hangul syllable sequences and Indic conjuncts, are not applied.
+func graphemeBreak(prev, r rune, riCount int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return false
+	case prev == '\n' || prev == '\r' || r == '\n' || r == '\r':
+		return true
+	case unicode.Is(unicode.M, r): // combining marks, including Mc spacing marks
+		return false
+	case r == zwj || isVariationSelector(r) || isEmojiModifier(r):
+		return false
+	case prev == zwj && unicode.Is(unicode.So, r):
+		// 👩 ZWJ 💻 forms a single emoji. The rules only join pictographs
+		// this way, which are symbols; a letter or space after a joiner
+		// starts a new cluster.
+		return false
+	case isRegionalIndicator(r) && isRegionalIndicator(prev):
+		// Two regional indicators form a flag; a third one starts a new one.
+		return riCount%2 == 0
+	}
+	return true
+}
+
+// zwj is the zero width joiner.
+const zwj = '\u200d'
+
+func isVariationSelector(r rune) bool {
+	return (r >= 0xfe00 && r <= 0xfe0f) || (r >= 0xe0100 && r <= 0xe01ef)
+}
+
+func isEmojiModifier(r rune) bool {
+	return r >= 0x1f3fb && r <= 0x1f3ff
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,42 @@
+package textx
+
+import "strings"
+
+// Reverse returns s with its grapheme clusters in reverse order, so that
+// combining marks stay on the letter they belong to and emoji sequences
+// stay whole: Reverse("noël") is "lëon", not "l̈eon".
+func Reverse(s string) string {
+	gs := Graphemes(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := len(gs) - 1; i >= 0; i-- {
+		b.WriteString(gs[i])
+	}
+	return b.String()
+}
+
+// Ellipsis is what Truncate puts in place of the text it removes.
+const Ellipsis = "…"
+
+// Truncate shortens s to at most n grapheme clusters. If s is longer, it
+// keeps the first n-1 and adds Ellipsis, so the result is still n
+// characters long. It never splits a grapheme cluster.
+func Truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	end, count := 0, 0
+	for end < len(s) {
+		if count == n-1 {
+			// If only one cluster is left, it fits in place of the
+			// ellipsis.
+			if end+nextGrapheme(s[end:]) == len(s) {
+				return s
+			}
+			return s[:end] + Ellipsis
+		}
+		end += nextGrapheme(s[end:])
+		count++
+	}
+	return s
+}
@@ -0,0 +1,252 @@
+package textx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const (
+	family = "👩‍👩‍👧" // three emoji joined by zero width joiners
+	flags  = "🇫🇷🇩🇪"  // two flags, four regional indicators
+	noel   = "noël" // ë as e plus a combining diaeresis
+)
+
+// seeds are strings for the fuzz tests, including invalid UTF-8.
+var seeds = []string{
+	"", "hello", noel, family, flags, "a\r\nb", "日本語", "Straße",
+	"\xff", "a\xffb", "e\xcc", "\xe2\x82", "́start", "🇫🇷🇩",
+}
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{noel, []string{"n", "o", "ë", "l"}},
+		{family + "!", []string{family, "!"}},
+		{flags + "🇩", []string{"🇫🇷", "🇩🇪", "🇩"}},
+		{"👍🏽x", []string{"👍🏽", "x"}},
+		{"a\r\n\n", []string{"a", "\r\n", "\n"}},
+		{"e\xff́", []string{"e", "\xff", "́"}},
+		{"a\u200d b", []string{"a\u200d", " ", "b"}},
+	}
+	for _, tt := range tests {
+		if got := Graphemes(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Graphemes(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := GraphemeCount(tt.in); got != len(tt.want) {
+			t.Errorf("GraphemeCount(%q) = %d, want %d", tt.in, got, len(tt.want))
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{noel, "lëon"},
+		{"ab" + flags, "🇩🇪🇫🇷ba"},
+		{"a\xffb", "b\xffa"},
+	}
+	for _, tt := range tests {
+		if got := Reverse(tt.in); got != tt.want {
+			t.Errorf("Reverse(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"hello", 0, ""},
+		{"hello", 5, "hello"},
+		{"hello", 4, "hel…"},
+		{"hello", 1, "…"},
+		{noel, 3, "no…"},
+		{family + family, 1, "…"},
+		{family + family, 2, family + family},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.in, tt.n); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{noel, 4},
+		{"日本語", 6},
+		{family, 2},
+		{"❤️", 2},
+		{"a\xffb", 3},
+		{"\t", 0},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.in); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	if got := PadRight("日本", 6) + "|"; got != "日本  |" {
+		t.Errorf("PadRight = %q", got)
+	}
+	if got := PadLeft("ab", 3); got != " ab" {
+		t.Errorf("PadLeft = %q", got)
+	}
+	if got := Center("ab", 5); got != " ab  " {
+		t.Errorf("Center = %q", got)
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"STRASSE", "straße", true},
+		{"ﬁne", "FINE", true},
+		{"ΣΊΣΥΦΟΣ", "σίσυφος", true},
+		{"Kelvin", "Kelvin", true},
+		{"a\xff", "A\xff", true},
+		{"a\xff", "a\xfe", false},
+		{"é", "é", false}, // not normalized
+	}
+	for _, tt := range tests {
+		if got := EqualFold(tt.a, tt.b); got != tt.want {
+			t.Errorf("EqualFold(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if got := CompareFold("apple", "BANANA"); got != -1 {
+		t.Errorf("CompareFold(apple, BANANA) = %d, want -1", got)
+	}
+}
+
+func addSeeds(f *testing.F) {
+	for _, s := range seeds {
+		f.Add(s)
+	}
+}
+
+func FuzzGraphemes(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, s string) {
+		gs := Graphemes(s)
+		if got := strings.Join(gs, ""); got != s {
+			t.Fatalf("Graphemes(%q) joined is %q", s, got)
+		}
+		if GraphemeCount(s) != len(gs) {
+			t.Fatalf("GraphemeCount(%q) = %d, but Graphemes gives %d", s, GraphemeCount(s), len(gs))
+		}
+		// An invalid byte is a cluster on its own.
+		for _, g := range gs {
+			if g == "" || (!utf8.ValidString(g) && len(g) != 1) {
+				t.Fatalf("Graphemes(%q) has the cluster %q", s, g)
+			}
+		}
+		// GraphemeBreaker, fed the runes one at a time, finds the same
+		// boundaries.
+		var b GraphemeBreaker
+		n := 0
+		for _, r := range s {
+			if b.Next(r) {
+				n++
+			}
+		}
+		if n != len(gs) {
+			t.Fatalf("GraphemeBreaker finds %d clusters in %q, Graphemes %d", n, s, len(gs))
+		}
+	})
+}
+
+func FuzzReverse(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, s string) {
+		gs := Graphemes(s)
+		var want strings.Builder
+		for i := len(gs) - 1; i >= 0; i-- {
+			want.WriteString(gs[i])
+		}
+		if got := Reverse(s); got != want.String() {
+			t.Fatalf("Reverse(%q) = %q, want %q", s, got, want.String())
+		}
+	})
+}
+
+func FuzzTruncate(f *testing.F) {
+	for _, s := range seeds {
+		f.Add(s, 2)
+	}
+	f.Fuzz(func(t *testing.T, s string, n int) {
+		n %= 20
+		got := Truncate(s, n)
+		count := GraphemeCount(s)
+		switch {
+		case n <= 0:
+			if got != "" {
+				t.Fatalf("Truncate(%q, %d) = %q, want \"\"", s, n, got)
+			}
+		case count <= n:
+			if got != s {
+				t.Fatalf("Truncate(%q, %d) = %q, want it unchanged", s, n, got)
+			}
+		default:
+			kept := strings.Join(Graphemes(s)[:n-1], "")
+			if got != kept+Ellipsis {
+				t.Fatalf("Truncate(%q, %d) = %q, want %q", s, n, got, kept+Ellipsis)
+			}
+		}
+	})
+}
+
+func FuzzWidth(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, s string) {
+		w := Width(s)
+		if w < 0 || w > 2*GraphemeCount(s) {
+			t.Fatalf("Width(%q) = %d for %d clusters", s, w, GraphemeCount(s))
+		}
+		if got := Width(PadRight(s, w+3)); got != w+3 {
+			t.Fatalf("Width(PadRight(%q, %d)) = %d", s, w+3, got)
+		}
+	})
+}
+
+func FuzzFold(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, s string) {
+		folded := Fold(s)
+		if Fold(folded) != folded {
+			t.Fatalf("Fold is not idempotent on %q: %q then %q", s, folded, Fold(folded))
+		}
+		// Invalid bytes are kept: folding never adds or removes them.
+		if invalid(folded) != invalid(s) {
+			t.Fatalf("Fold(%q) = %q changed the invalid bytes", s, folded)
+		}
+		if utf8.ValidString(s) && !EqualFold(strings.ToUpper(s), s) {
+			t.Fatalf("EqualFold(ToUpper(%q), %q) is false", s, s)
+		}
+	})
+}
+
+// invalid returns the bytes of s that are not part of valid UTF-8.
+func invalid(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteByte(s[i])
+		}
+		i += size
+	}
+	return b.String()
+}
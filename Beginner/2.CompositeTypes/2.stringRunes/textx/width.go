@@ -0,0 +1,147 @@
+package textx
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Width returns the number of columns s takes in a terminal using a
+// monospaced font. East Asian wide and fullwidth characters and most
+// emoji take two columns, combining marks and control characters none,
+// and everything else one. Each invalid byte takes one column, as
+// terminals show it as a replacement character.
+func Width(s string) int {
+	w := 0
+	for len(s) > 0 {
+		n := nextGrapheme(s)
+		w += graphemeWidth(s[:n])
+		s = s[n:]
+	}
+	return w
+}
+
+// graphemeWidth returns the width of one grapheme cluster, which is that
+// of its first rune, or two if it asks for emoji presentation with the
+// variation selector U+FE0F.
+func graphemeWidth(g string) int {
+	r, _ := utf8.DecodeRuneInString(g)
+	switch {
+	case r == utf8.RuneError:
+		return 1
+	case unicode.IsControl(r) || unicode.Is(unicode.M, r) || r == zwj:
+		return 0
+	case isWide(r) || strings.ContainsRune(g, '\ufe0f'):
+		return 2
+	}
+	return 1
+}
+
+// wideRanges are the main blocks of East Asian Wide (W) and Fullwidth (F)
+// characters in Unicode's EastAsianWidth.txt, and the emoji blocks, which
+// are shown two columns wide.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115f},   // Hangul Jamo initial consonants
+	{0x231a, 0x231b},   // watch, hourglass
+	{0x2329, 0x232a},   // angle brackets
+	{0x23e9, 0x23ec},   // media control symbols
+	{0x23f0, 0x23f0},   // alarm clock
+	{0x23f3, 0x23f3},   // hourglass with flowing sand
+	{0x25fd, 0x25fe},   // medium small squares
+	{0x2614, 0x2615},   // umbrella with rain, hot beverage
+	{0x2648, 0x2653},   // zodiac signs
+	{0x26a1, 0x26a1},   // high voltage
+	{0x26aa, 0x26ab},   // medium circles
+	{0x26bd, 0x26be},   // soccer ball, baseball
+	{0x26c4, 0x26c5},   // snowman, sun behind cloud
+	{0x26d4, 0x26d4},   // no entry
+	{0x26ea, 0x26ea},   // church
+	{0x26f2, 0x26f5},   // fountain to sailboat
+	{0x26fa, 0x26fa},   // tent
+	{0x26fd, 0x26fd},   // fuel pump
+	{0x2705, 0x2705},   // check mark button
+	{0x270a, 0x270b},   // raised fist, raised hand
+	{0x2728, 0x2728},   // sparkles
+	{0x274c, 0x274c},   // cross mark
+	{0x2753, 0x2755},   // question and exclamation marks
+	{0x2757, 0x2757},   // exclamation mark
+	{0x2795, 0x2797},   // plus, minus, divide
+	{0x27b0, 0x27b0},   // curly loop
+	{0x27bf, 0x27bf},   // double curly loop
+	{0x2b1b, 0x2b1c},   // large squares
+	{0x2b50, 0x2b50},   // star
+	{0x2b55, 0x2b55},   // large circle
+	{0x2e80, 0x303e},   // CJK radicals, Kangxi, CJK symbols and punctuation
+	{0x3041, 0x33ff},   // Hiragana, Katakana, Bopomofo, Hangul compatibility Jamo, CJK compatibility
+	{0x3400, 0x4dbf},   // CJK unified ideographs extension A
+	{0x4e00, 0x9fff},   // CJK unified ideographs
+	{0xa000, 0xa4cf},   // Yi
+	{0xa960, 0xa97f},   // Hangul Jamo extended A
+	{0xac00, 0xd7a3},   // Hangul syllables
+	{0xf900, 0xfaff},   // CJK compatibility ideographs
+	{0xfe10, 0xfe19},   // vertical forms
+	{0xfe30, 0xfe6f},   // CJK compatibility forms, small form variants
+	{0xff00, 0xff60},   // fullwidth forms
+	{0xffe0, 0xffe6},   // fullwidth signs
+	{0x16fe0, 0x18aff}, // Tangut and others
+	{0x1b000, 0x1b2ff}, // Kana supplement and extensions, Nushu
+	{0x1f004, 0x1f004}, // mahjong red dragon
+	{0x1f0cf, 0x1f0cf}, // playing card black joker
+	{0x1f18e, 0x1f18e}, // AB button
+	{0x1f191, 0x1f19a}, // squared words
+	{0x1f1e6, 0x1f1ff}, // regional indicators, which pair into flags
+	{0x1f200, 0x1f2ff}, // enclosed ideographic supplement
+	{0x1f300, 0x1f64f}, // miscellaneous symbols and pictographs, emoticons
+	{0x1f680, 0x1f6ff}, // transport and map symbols
+	{0x1f7e0, 0x1f7eb}, // large coloured circles and squares
+	{0x1f90c, 0x1f9ff}, // supplemental symbols and pictographs
+	{0x1fa70, 0x1faff}, // symbols and pictographs extended A
+	{0x20000, 0x2fffd}, // CJK unified ideographs extensions B to F
+	{0x30000, 0x3fffd}, // CJK unified ideographs extension G and beyond
+}
+
+// isWide reports whether r is in one of wideRanges.
+func isWide(r rune) bool {
+	if r < wideRanges[0].lo {
+		return false
+	}
+	lo, hi := 0, len(wideRanges)
+	for lo < hi {
+		m := (lo + hi) / 2
+		switch {
+		case r < wideRanges[m].lo:
+			hi = m
+		case r > wideRanges[m].hi:
+			lo = m + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// PadRight returns s followed by enough spaces to make it width columns
+// wide. A string already that wide or wider is returned unchanged.
+func PadRight(s string, width int) string {
+	return s + strings.Repeat(" ", padding(s, width))
+}
+
+// PadLeft returns s preceded by enough spaces to make it width columns
+// wide, for right aligning it.
+func PadLeft(s string, width int) string {
+	return strings.Repeat(" ", padding(s, width)) + s
+}
+
+// Center returns s with spaces on both sides making it width columns
+// wide. An odd space goes on the right.
+func Center(s string, width int) string {
+	p := padding(s, width)
+	return strings.Repeat(" ", p/2) + s + strings.Repeat(" ", p-p/2)
+}
+
+func padding(s string, width int) int {
+	if w := Width(s); w < width {
+		return width - w
+	}
+	return 0
+}
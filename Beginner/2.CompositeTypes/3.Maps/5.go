@@ -1,4 +1,49 @@
-/*5. **Map of Functions:** 
+/*5. **Map of Functions:**
 Create a map that associates math operations (addition, subtraction, multiplication, division)
  with corresponding functions. Allow the user to select an operation and perform it on two numbers.*/
 
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+func main() {
+	operations := map[string]func(float64, float64) (float64, error){
+		"+": func(x, y float64) (float64, error) { return x + y, nil },
+		"-": func(x, y float64) (float64, error) { return x - y, nil },
+		"*": func(x, y float64) (float64, error) { return x * y, nil },
+		"/": func(x, y float64) (float64, error) {
+			if y == 0 {
+				return 0, errors.New("cannot divide by zero")
+			}
+			return x / y, nil
+		},
+	}
+
+	var x, y float64
+	var op string
+	fmt.Print("enter the first number: ")
+	fmt.Scanln(&x)
+	fmt.Print("enter an operation (+ - * /): ")
+	fmt.Scanln(&op)
+	fmt.Print("enter the second number: ")
+	fmt.Scanln(&y)
+
+	f, ok := operations[op] // comma ok idiom tells a missing key apart from a zero value
+	if !ok {
+		fmt.Println("unknown operation", op)
+		return
+	}
+	result, err := f(x, y)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("result:", result)
+}
+
+// The dispatch package in this directory grows this map into a command
+// dispatcher with aliases, usage text and suggestions for typos; see
+// dispatch/cmd/mathops.
@@ -0,0 +1,91 @@
+// Command mathops performs the arithmetic operations from the Maps
+// exercise on two numbers, with the operation chosen by name:
+//
+//	mathops add 2 3
+//	mathops / 7 2
+//	mathops help
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/learning-go-book/dispatch"
+)
+
+// operations maps each operation to the function performing it.
+var operations = map[string]func(x, y float64) (float64, error){
+	"add":      func(x, y float64) (float64, error) { return x + y, nil },
+	"subtract": func(x, y float64) (float64, error) { return x - y, nil },
+	"multiply": func(x, y float64) (float64, error) { return x * y, nil },
+	"divide": func(x, y float64) (float64, error) {
+		if y == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return x / y, nil
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run carries out the operation named in args, writing the result to out
+// and errors to errOut, and returns the exit status: 2 for a usage error,
+// 1 for any other.
+func run(args []string, out, errOut io.Writer) int {
+	d := dispatch.New("mathops")
+	d.Out = out
+	for _, op := range []struct {
+		name, symbol, summary string
+	}{
+		{"add", "+", "add two numbers"},
+		{"subtract", "-", "subtract y from x"},
+		{"multiply", "*", "multiply two numbers"},
+		{"divide", "/", "divide x by y"},
+	} {
+		d.MustRegister(dispatch.Command{
+			Name:    op.name,
+			Aliases: []string{op.symbol},
+			Args:    []dispatch.Arg{{Name: "x"}, {Name: "y"}},
+			Summary: op.summary,
+			Run:     binary(out, operations[op.name]),
+		})
+	}
+
+	err := d.Dispatch(args)
+	var usage *dispatch.UsageError
+	switch {
+	case errors.As(err, &usage):
+		fmt.Fprintf(errOut, "mathops: %v\n%s\n", err, usage.Usage)
+		return 2
+	case err != nil:
+		fmt.Fprintln(errOut, "mathops:", err)
+		return 1
+	}
+	return 0
+}
+
+// binary turns an operation into a command handler that parses its two
+// arguments and writes the result to out.
+func binary(out io.Writer, op func(x, y float64) (float64, error)) func([]string) error {
+	return func(args []string) error {
+		x, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", args[0])
+		}
+		y, err := strconv.ParseFloat(args[1], 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", args[1])
+		}
+		r, err := op(x, y)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, r)
+		return err
+	}
+}
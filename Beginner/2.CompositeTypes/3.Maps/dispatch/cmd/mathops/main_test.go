@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		args     []string
+		status   int
+		out, err string
+	}{
+		{[]string{"add", "2", "3"}, 0, "5\n", ""},
+		{[]string{"+", "0.1", "0.2"}, 0, "0.30000000000000004\n", ""},
+		{[]string{"subtract", "2", "3"}, 0, "-1\n", ""},
+		{[]string{"*", "-4", "2.5"}, 0, "-10\n", ""},
+		{[]string{"/", "7", "2"}, 0, "3.5\n", ""},
+		{[]string{"help", "/"}, 0, "usage: mathops divide <x> <y>\n\ndivide x by y\n\naliases: /\n", ""},
+
+		{[]string{"divide", "1", "0"}, 1, "", "mathops: division by zero\n"},
+		{[]string{"add", "two", "3"}, 1, "", "mathops: \"two\" is not a number\n"},
+		{[]string{"add", "2", "3x"}, 1, "", "mathops: \"3x\" is not a number\n"},
+		{[]string{"ad", "2", "3"}, 1, "", "mathops: unknown command \"ad\"; did you mean \"add\"?\n"},
+		{[]string{"x", "2", "3"}, 1, "", "mathops: unknown command \"x\"\n"},
+		{[]string{"add", "2"}, 2, "", "mathops: add takes 2 arguments, got 1\nusage: mathops add <x> <y>\n"},
+	}
+	for _, tt := range tests {
+		var out, errOut strings.Builder
+		status := run(tt.args, &out, &errOut)
+		if status != tt.status || out.String() != tt.out || errOut.String() != tt.err {
+			t.Errorf("mathops %s: status %d, wrote %q and %q; want %d, %q and %q",
+				strings.Join(tt.args, " "), status, out.String(), errOut.String(), tt.status, tt.out, tt.err)
+		}
+	}
+}
+
+// TestRunUsage checks that the usage error for no arguments and the
+// output of help both list every operation.
+func TestRunUsage(t *testing.T) {
+	tests := []struct {
+		args   []string
+		status int
+	}{
+		{nil, 2},
+		{[]string{"help"}, 0},
+	}
+	for _, tt := range tests {
+		var out, errOut strings.Builder
+		if status := run(tt.args, &out, &errOut); status != tt.status {
+			t.Errorf("mathops %q: status %d, want %d", tt.args, status, tt.status)
+		}
+		text := out.String() + errOut.String()
+		for _, want := range []string{"add <x> <y>", "subtract <x> <y>", "multiply <x> <y>", "divide <x> <y>", "(also /)"} {
+			if !strings.Contains(text, want) {
+				t.Errorf("mathops %q wrote %q, which does not mention %q", tt.args, text, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,318 @@
+// Package dispatch runs commands looked up by name in a map, the "map of
+// functions" idea from the Maps exercises grown into something programs
+// with subcommands can share. cmd/mathops uses it for its operations;
+// package calc registers the operators of its opMap and its functions as
+// commands with Env.Commands, which the calc command runs for arguments
+// such as "add 2 3"; and the calc REPL uses it for its :commands.
+// Programs configured only by flags,
+// such as textstats and webserver, have no subcommands to dispatch and
+// keep using the flag package.
+//
+// Each Command has a name, optional aliases, a description of its
+// arguments and a function to run. The Dispatcher checks the number of
+// arguments, writes usage text from the descriptions, and suggests the
+// nearest command when a name is mistyped:
+//
+//	d := dispatch.New("mathops")
+//	d.Register(dispatch.Command{
+//		Name:    "add",
+//		Aliases: []string{"+", "plus"},
+//		Args:    []dispatch.Arg{{Name: "x"}, {Name: "y"}},
+//		Summary: "add two numbers",
+//		Run:     add,
+//	})
+//	err := d.Dispatch(os.Args[1:])
+package dispatch
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Arg describes one argument of a command.
+type Arg struct {
+	Name     string
+	Optional bool // may be left out; only trailing arguments can be optional
+	Variadic bool // takes all remaining arguments; must be the last
+}
+
+// Command is something a Dispatcher can run.
+type Command struct {
+	Name    string
+	Aliases []string
+	Args    []Arg
+	Summary string // one line description for the usage text
+	// Run is called with the arguments after the command name, once
+	// their number has been checked against Args.
+	Run func(args []string) error
+}
+
+// usage returns the command's synopsis, e.g. "add <x> <y>".
+func (c *Command) usage() string {
+	parts := []string{c.Name}
+	for _, a := range c.Args {
+		name := a.Name
+		if a.Variadic {
+			name += "..."
+		}
+		if a.Optional {
+			parts = append(parts, "["+name+"]")
+		} else {
+			parts = append(parts, "<"+name+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// arity returns the smallest and largest number of arguments c takes;
+// max is -1 if there is no limit.
+func (c *Command) arity() (min, max int) {
+	for _, a := range c.Args {
+		if !a.Optional {
+			min++
+		}
+		if a.Variadic {
+			return min, -1
+		}
+	}
+	return min, len(c.Args)
+}
+
+// Dispatcher holds a set of commands.
+type Dispatcher struct {
+	Name string    // program name used in usage text
+	Out  io.Writer // where help is written; os.Stdout if nil
+
+	commands map[string]*Command // by name and alias
+	order    []*Command          // as registered
+}
+
+// New returns a Dispatcher for the program name with no commands. It
+// understands "help" and "help <command>" itself, unless a command named
+// help is registered.
+func New(name string) *Dispatcher {
+	return &Dispatcher{Name: name, commands: map[string]*Command{}}
+}
+
+// Register adds c. It fails if c has no name or Run function, if its
+// Args are out of order, or if its name or an alias is already used.
+func (d *Dispatcher) Register(c Command) error {
+	if c.Name == "" || c.Run == nil {
+		return fmt.Errorf("dispatch: command %q needs a name and a Run function", c.Name)
+	}
+	for i, a := range c.Args {
+		last := i == len(c.Args)-1
+		if a.Variadic && !last {
+			return fmt.Errorf("dispatch: %s: variadic argument %s must be last", c.Name, a.Name)
+		}
+		if !a.Optional && i > 0 && c.Args[i-1].Optional {
+			return fmt.Errorf("dispatch: %s: required argument %s follows an optional one", c.Name, a.Name)
+		}
+	}
+	names := append([]string{c.Name}, c.Aliases...)
+	for _, n := range names {
+		if _, ok := d.commands[n]; ok {
+			return fmt.Errorf("dispatch: %q is already registered", n)
+		}
+	}
+	p := &c
+	for _, n := range names {
+		d.commands[n] = p
+	}
+	d.order = append(d.order, p)
+	return nil
+}
+
+// MustRegister is like Register but panics on failure. It is meant for
+// programs registering a fixed set of commands at start up.
+func (d *Dispatcher) MustRegister(cmds ...Command) {
+	for _, c := range cmds {
+		if err := d.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Lookup returns the command with the given name or alias.
+func (d *Dispatcher) Lookup(name string) (*Command, bool) {
+	c, ok := d.commands[name]
+	return c, ok
+}
+
+// Dispatch runs the command named by args[0] with the rest of args. It
+// returns an *UnknownCommandError for a name it does not know, a
+// *UsageError if the number of arguments is wrong, and otherwise what the
+// command returns.
+func (d *Dispatcher) Dispatch(args []string) error {
+	if len(args) == 0 {
+		return &UsageError{Msg: "no command given", Usage: strings.TrimSuffix(d.Usage(), "\n")}
+	}
+	name, rest := args[0], args[1:]
+	c, ok := d.commands[name]
+	if !ok {
+		if name == "help" {
+			return d.help(rest)
+		}
+		return &UnknownCommandError{Name: name, Suggestions: d.Suggest(name)}
+	}
+	min, max := c.arity()
+	if len(rest) < min || (max >= 0 && len(rest) > max) {
+		return &UsageError{
+			Msg:   fmt.Sprintf("%s takes %s, got %d", c.Name, describeArity(min, max), len(rest)),
+			Usage: "usage: " + d.prefix() + c.usage(),
+		}
+	}
+	return c.Run(rest)
+}
+
+// help writes the usage of the command named in args, or of all of them.
+func (d *Dispatcher) help(args []string) error {
+	out := d.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	if len(args) == 0 {
+		_, err := io.WriteString(out, d.Usage())
+		return err
+	}
+	c, ok := d.commands[args[0]]
+	if !ok {
+		return &UnknownCommandError{Name: args[0], Suggestions: d.Suggest(args[0])}
+	}
+	text := "usage: " + d.prefix() + c.usage() + "\n"
+	if c.Summary != "" {
+		text += "\n" + c.Summary + "\n"
+	}
+	if len(c.Aliases) > 0 {
+		text += "\naliases: " + strings.Join(c.Aliases, ", ") + "\n"
+	}
+	_, err := io.WriteString(out, text)
+	return err
+}
+
+// prefix returns the program name followed by a space, or nothing.
+func (d *Dispatcher) prefix() string {
+	if d.Name == "" {
+		return ""
+	}
+	return d.Name + " "
+}
+
+// Usage returns a description of every command, in the order they were
+// registered, with their arguments, aliases and summaries aligned.
+func (d *Dispatcher) Usage() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "usage: %s<command> [arguments]\n\ncommands:\n", d.prefix())
+	b.WriteString(d.CommandList())
+	if _, ok := d.commands["help"]; !ok {
+		fmt.Fprintf(&b, "\nRun \"%shelp <command>\" for more about a command.\n", d.prefix())
+	}
+	return b.String()
+}
+
+// CommandList returns the part of Usage listing the commands, one per
+// line, for programs that write their own help around it.
+func (d *Dispatcher) CommandList() string {
+	var b strings.Builder
+	width := 0
+	for _, c := range d.order {
+		if n := len(c.usage()); n > width {
+			width = n
+		}
+	}
+	for _, c := range d.order {
+		summary := c.Summary
+		if len(c.Aliases) > 0 {
+			summary += " (also " + strings.Join(c.Aliases, ", ") + ")"
+		}
+		fmt.Fprintf(&b, "  %-*s  %s\n", width, c.usage(), strings.TrimSpace(summary))
+	}
+	return b.String()
+}
+
+// Suggest returns the command names and aliases close to name, nearest
+// first: those within a few edits of it, and those it is a prefix of. A
+// single character is one edit from every other short name, so for one
+// only the names it is a prefix of are suggested.
+func (d *Dispatcher) Suggest(name string) []string {
+	type candidate struct {
+		name string
+		dist int
+	}
+	var cands []candidate
+	limit := len([]rune(name))/3 + 1
+	if len([]rune(name)) <= 1 {
+		limit = 0
+	}
+	for n := range d.commands {
+		dist := editDistance(name, n)
+		if dist <= limit || (len(name) > 0 && strings.HasPrefix(n, name)) {
+			cands = append(cands, candidate{n, dist})
+		}
+	}
+	sort.Slice(cands, func(i, j int) bool {
+		if cands[i].dist != cands[j].dist {
+			return cands[i].dist < cands[j].dist
+		}
+		return cands[i].name < cands[j].name
+	})
+	names := make([]string, len(cands))
+	for i, c := range cands {
+		names[i] = c.name
+	}
+	return names
+}
+
+// editDistance returns the Levenshtein distance between a and b: the
+// number of runes to insert, delete or replace to turn one into the other.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// describeArity describes how many arguments a command takes.
+func describeArity(min, max int) string {
+	switch {
+	case max < 0:
+		return fmt.Sprintf("at least %d argument%s", min, plural(min))
+	case min == max:
+		return fmt.Sprintf("%d argument%s", min, plural(min))
+	}
+	return fmt.Sprintf("%d to %d arguments", min, max)
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,170 @@
+package dispatch
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"add", "add", 0},
+		{"add", "ad", 1},
+		{"sub", "sbu", 2},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"naïve", "naive", 1}, // runes, not bytes
+		{"日本", "日本語", 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := editDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestEditDistanceProperties(t *testing.T) {
+	// It is a metric, and never more than the longer string's length nor
+	// less than the difference in lengths.
+	f := func(a, b, c string) bool {
+		ab, bc, ac := editDistance(a, b), editDistance(b, c), editDistance(a, c)
+		la, lb := len([]rune(a)), len([]rune(b))
+		longer, diff := la, la-lb
+		if lb > la {
+			longer, diff = lb, lb-la
+		}
+		return ab == editDistance(b, a) &&
+			(ab == 0) == (a == b) &&
+			ac <= ab+bc &&
+			ab <= longer && ab >= diff
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func newTestDispatcher(t *testing.T) *Dispatcher {
+	t.Helper()
+	d := New("prog")
+	run := func([]string) error { return nil }
+	d.MustRegister(
+		Command{Name: "add", Aliases: []string{"plus"}, Args: []Arg{{Name: "x"}, {Name: "y"}}, Summary: "add two numbers", Run: run},
+		Command{Name: "sub", Run: run},
+		Command{Name: "sum", Args: []Arg{{Name: "n", Variadic: true}}, Run: run},
+		Command{Name: "status", Args: []Arg{{Name: "verbose", Optional: true}}, Run: run},
+	)
+	return d
+}
+
+func TestSuggest(t *testing.T) {
+	d := newTestDispatcher(t)
+	run := func([]string) error { return nil }
+	d.MustRegister(
+		Command{Name: "+", Run: run},
+		Command{Name: "-", Run: run},
+	)
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"ad", []string{"add"}},                 // a deletion away
+		{"sbu", []string{"sub", "sum"}},         // equally near, by name
+		{"s", []string{"sub", "sum", "status"}}, // prefixes, however far
+		{"stat", []string{"status"}},            // within 4/3+1 edits
+		{"plsu", []string{"plus"}},              // aliases count
+		{"multiply", []string{}},
+		{"", []string{}},        // not a prefix of everything
+		{"x", []string{}},       // one edit from + and -, but they are not suggested
+		{"*", []string{}},       // nor for another symbol
+		{"p", []string{"plus"}}, // a single character still finds what it starts
+		{"+x", []string{"+"}},   // two runes may be one edit away
+	}
+	for _, tt := range tests {
+		if got := d.Suggest(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	d := newTestDispatcher(t)
+	var got []string
+	d.commands["add"].Run = func(args []string) error {
+		got = args
+		return nil
+	}
+	if err := d.Dispatch([]string{"plus", "1", "2"}); err != nil || !reflect.DeepEqual(got, []string{"1", "2"}) {
+		t.Errorf("Dispatch(plus 1 2) = %v, ran with %q", err, got)
+	}
+
+	var unknown *UnknownCommandError
+	err := d.Dispatch([]string{"ad", "1", "2"})
+	if !errors.As(err, &unknown) || err.Error() != `unknown command "ad"; did you mean "add"?` {
+		t.Errorf("Dispatch(ad) = %v", err)
+	}
+
+	var usage *UsageError
+	for _, args := range [][]string{{}, {"add", "1"}, {"status", "a", "b"}, {"sub", "x"}, {"sum"}} {
+		if err := d.Dispatch(args); !errors.As(err, &usage) {
+			t.Errorf("Dispatch(%q) = %v, want a UsageError", args, err)
+		}
+	}
+	for _, args := range [][]string{{"sum", "1"}, {"sum", "1", "2", "3"}, {"status"}, {"status", "-v"}} {
+		if err := d.Dispatch(args); err != nil {
+			t.Errorf("Dispatch(%q) = %v", args, err)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	d := newTestDispatcher(t)
+	run := func([]string) error { return nil }
+	for _, c := range []Command{
+		{Name: "", Run: run},
+		{Name: "nil"},
+		{Name: "add", Run: run},
+		{Name: "new", Aliases: []string{"plus"}, Run: run},
+		{Name: "v", Args: []Arg{{Name: "a", Variadic: true}, {Name: "b"}}, Run: run},
+		{Name: "o", Args: []Arg{{Name: "a", Optional: true}, {Name: "b"}}, Run: run},
+	} {
+		if err := d.Register(c); err == nil {
+			t.Errorf("Register(%+v) succeeded", c)
+		}
+	}
+}
+
+func TestHelp(t *testing.T) {
+	d := newTestDispatcher(t)
+	var out strings.Builder
+	d.Out = &out
+	if err := d.Dispatch([]string{"help", "plus"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "usage: prog add <x> <y>\n\nadd two numbers\n\naliases: plus\n"
+	if out.String() != want {
+		t.Errorf("help plus wrote %q, want %q", out.String(), want)
+	}
+	want = `usage: prog <command> [arguments]
+
+commands:
+  add <x> <y>       add two numbers (also plus)
+  sub               
+  sum <n...>        
+  status [verbose]  
+
+Run "prog help <command>" for more about a command.
+`
+	if got := d.Usage(); got != want {
+		t.Errorf("Usage() = %q, want %q", got, want)
+	}
+}
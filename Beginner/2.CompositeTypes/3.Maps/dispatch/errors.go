@@ -0,0 +1,40 @@
+package dispatch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownCommandError is returned by Dispatch for a command name that is
+// not registered.
+type UnknownCommandError struct {
+	Name        string
+	Suggestions []string // nearest known names, best first
+}
+
+func (e *UnknownCommandError) Error() string {
+	msg := fmt.Sprintf("unknown command %q", e.Name)
+	switch len(e.Suggestions) {
+	case 0:
+		return msg
+	case 1:
+		return fmt.Sprintf("%s; did you mean %q?", msg, e.Suggestions[0])
+	}
+	quoted := make([]string, 0, 3)
+	for _, s := range e.Suggestions {
+		if len(quoted) == 3 {
+			break
+		}
+		quoted = append(quoted, fmt.Sprintf("%q", s))
+	}
+	return fmt.Sprintf("%s; did you mean one of %s?", msg, strings.Join(quoted, ", "))
+}
+
+// UsageError is returned by Dispatch when a command is given the wrong
+// number of arguments, or none is given at all.
+type UsageError struct {
+	Msg   string
+	Usage string // usage text for the command, or for all commands, without a final newline
+}
+
+func (e *UsageError) Error() string { return e.Msg }
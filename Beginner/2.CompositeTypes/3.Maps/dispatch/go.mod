@@ -0,0 +1,3 @@
+module github.com/learning-go-book/dispatch
+
+go 1.18
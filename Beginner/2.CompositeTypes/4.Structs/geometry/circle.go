@@ -0,0 +1,37 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// Circle is a circle centred on Location.
+type Circle struct {
+	Radius float64
+	Location
+}
+
+func (c Circle) Area() float64      { return math.Pi * c.Radius * c.Radius }
+func (c Circle) Perimeter() float64 { return 2 * math.Pi * c.Radius }
+
+func (c Circle) Bounds() Rectangle {
+	return Rectangle{
+		Width:    2 * c.Radius,
+		Height:   2 * c.Radius,
+		Location: Location{X: c.X - c.Radius, Y: c.Y - c.Radius},
+	}
+}
+
+// Contains reports whether p is inside c or on its edge.
+func (c Circle) Contains(p Location) bool {
+	return c.Location.Distance(p) <= c.Radius
+}
+
+// Scale multiplies the radius of c by f.
+func (c *Circle) Scale(f float64) {
+	c.Radius *= f
+}
+
+func (c Circle) String() string {
+	return fmt.Sprintf("Circle r=%g at %v", c.Radius, c.Location)
+}
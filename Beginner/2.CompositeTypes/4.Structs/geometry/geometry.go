@@ -0,0 +1,124 @@
+// Package geometry provides two dimensional shapes.
+//
+// Coordinates follow the screen and SVG convention: X grows to the right
+// and Y grows downwards. Every shape embeds a Location, which places it
+// on the plane, so moving a shape is a matter of moving its Location:
+//
+//	r := geometry.Rectangle{Width: 10, Height: 4, Location: geometry.Location{X: 199, Y: 29}}
+//	r.Translate(1, -1) // promoted from Location
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// Location is a point on the plane.
+type Location struct {
+	X, Y float64
+}
+
+// Translate moves l by dx and dy. Shapes embed Location, so this also
+// moves a *Rectangle, *Circle or *Polygon.
+func (l *Location) Translate(dx, dy float64) {
+	l.X += dx
+	l.Y += dy
+}
+
+// Distance returns the distance between l and m.
+func (l Location) Distance(m Location) float64 {
+	return math.Hypot(m.X-l.X, m.Y-l.Y)
+}
+
+func (l Location) String() string {
+	return fmt.Sprintf("(%g, %g)", l.X, l.Y)
+}
+
+// Shape is a closed shape on the plane.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+	// Bounds returns the smallest axis aligned rectangle containing
+	// the shape.
+	Bounds() Rectangle
+}
+
+// Transformer is implemented by pointers to shapes that can be moved and
+// resized in place.
+type Transformer interface {
+	Translate(dx, dy float64)
+	// Scale multiplies the size of the shape by f, keeping its
+	// Location fixed.
+	Scale(f float64)
+}
+
+// Rectangle is an axis aligned rectangle whose top left corner is at
+// Location.
+type Rectangle struct {
+	Width, Height float64
+	Location
+}
+
+// Rect returns the rectangle with corners (x0, y0) and (x1, y1), which
+// may be given in either order.
+func Rect(x0, y0, x1, y1 float64) Rectangle {
+	return Rectangle{
+		Width:    math.Abs(x1 - x0),
+		Height:   math.Abs(y1 - y0),
+		Location: Location{X: math.Min(x0, x1), Y: math.Min(y0, y1)},
+	}
+}
+
+func (r Rectangle) Area() float64      { return r.Width * r.Height }
+func (r Rectangle) Perimeter() float64 { return 2 * (r.Width + r.Height) }
+func (r Rectangle) Bounds() Rectangle  { return r }
+
+// Max returns the bottom right corner of r.
+func (r Rectangle) Max() Location {
+	return Location{X: r.X + r.Width, Y: r.Y + r.Height}
+}
+
+// Center returns the centre of r.
+func (r Rectangle) Center() Location {
+	return Location{X: r.X + r.Width/2, Y: r.Y + r.Height/2}
+}
+
+// Contains reports whether p is inside r or on its edge.
+func (r Rectangle) Contains(p Location) bool {
+	return p.X >= r.X && p.X <= r.X+r.Width && p.Y >= r.Y && p.Y <= r.Y+r.Height
+}
+
+// ContainsRect reports whether s lies entirely inside r.
+func (r Rectangle) ContainsRect(s Rectangle) bool {
+	return r.Contains(s.Location) && r.Contains(s.Max())
+}
+
+// Overlaps reports whether r and s share at least one point.
+func (r Rectangle) Overlaps(s Rectangle) bool {
+	return r.X <= s.X+s.Width && s.X <= r.X+r.Width &&
+		r.Y <= s.Y+s.Height && s.Y <= r.Y+r.Height
+}
+
+// Union returns the smallest rectangle containing both r and s.
+func (r Rectangle) Union(s Rectangle) Rectangle {
+	rm, sm := r.Max(), s.Max()
+	return Rect(math.Min(r.X, s.X), math.Min(r.Y, s.Y), math.Max(rm.X, sm.X), math.Max(rm.Y, sm.Y))
+}
+
+// Scale multiplies the width and height of r by f.
+func (r *Rectangle) Scale(f float64) {
+	r.Width *= f
+	r.Height *= f
+}
+
+// polygon returns r as a Polygon with the same corners.
+func (r Rectangle) polygon() Polygon {
+	return Polygon{
+		Location: r.Location,
+		Points:   []Location{{0, 0}, {r.Width, 0}, {r.Width, r.Height}, {0, r.Height}},
+	}
+}
+
+func (r Rectangle) String() string {
+	return fmt.Sprintf("Rectangle %gx%g at %v", r.Width, r.Height, r.Location)
+}
@@ -0,0 +1,183 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+// near reports whether a and b are equal to within rounding error.
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+func nearRect(a, b Rectangle) bool {
+	return near(a.X, b.X) && near(a.Y, b.Y) && near(a.Width, b.Width) && near(a.Height, b.Height)
+}
+
+func TestShapes(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         Shape
+		area      float64
+		perimeter float64
+		bounds    Rectangle
+	}{
+		{"rectangle", Rect(1, 2, 5, 5), 12, 14, Rectangle{Width: 4, Height: 3, Location: Location{1, 2}}},
+		{"rectangle corners swapped", Rect(5, 5, 1, 2), 12, 14, Rectangle{Width: 4, Height: 3, Location: Location{1, 2}}},
+		{"empty rectangle", Rectangle{Location: Location{3, 3}}, 0, 0, Rectangle{Location: Location{3, 3}}},
+		{"unit circle", Circle{Radius: 1}, math.Pi, 2 * math.Pi, Rect(-1, -1, 1, 1)},
+		{"circle", Circle{Radius: 2.5, Location: Location{10, -4}}, 6.25 * math.Pi, 5 * math.Pi, Rect(7.5, -6.5, 12.5, -1.5)},
+		{"right triangle", NewPolygon(Location{0, 0}, Location{3, 0}, Location{0, 4}), 6, 12, Rect(0, 0, 3, 4)},
+		{
+			"square polygon, clockwise",
+			NewPolygon(Location{1, 1}, Location{1, 3}, Location{3, 3}, Location{3, 1}),
+			4, 8, Rect(1, 1, 3, 3),
+		},
+		{
+			// An L shape: a 4x4 square with its top right 2x2 corner cut out.
+			"concave L",
+			NewPolygon(Location{0, 0}, Location{2, 0}, Location{2, 2}, Location{4, 2}, Location{4, 4}, Location{0, 4}),
+			12, 16, Rect(0, 0, 4, 4),
+		},
+		{"polygon literal", Polygon{Points: []Location{{0, 0}, {2, 0}, {2, 2}}, Location: Location{10, 10}}, 2, 4 + 2*math.Sqrt2, Rect(10, 10, 12, 12)},
+		{"empty polygon", Polygon{Location: Location{7, 8}}, 0, 0, Rectangle{Location: Location{7, 8}}},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Area(); !near(got, tt.area) {
+			t.Errorf("%s: Area = %v, want %v", tt.name, got, tt.area)
+		}
+		if got := tt.s.Perimeter(); !near(got, tt.perimeter) {
+			t.Errorf("%s: Perimeter = %v, want %v", tt.name, got, tt.perimeter)
+		}
+		if got := tt.s.Bounds(); !nearRect(got, tt.bounds) {
+			t.Errorf("%s: Bounds = %v, want %v", tt.name, got, tt.bounds)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	r := Rect(0, 0, 10, 5)
+	c := Circle{Radius: 5, Location: Location{0, 0}}
+	tests := []struct {
+		name string
+		in   interface{ Contains(Location) bool }
+		p    Location
+		want bool
+	}{
+		{"rectangle inside", r, Location{5, 2}, true},
+		{"rectangle corner", r, Location{0, 0}, true},
+		{"rectangle far corner", r, Location{10, 5}, true},
+		{"rectangle edge", r, Location{10, 3}, true},
+		{"rectangle right", r, Location{10.001, 3}, false},
+		{"rectangle above", r, Location{5, -0.001}, false},
+		{"circle centre", c, Location{0, 0}, true},
+		{"circle edge", c, Location{3, 4}, true},
+		{"circle just outside", c, Location{3, 4.01}, false},
+		{"circle bounds corner", c, Location{4.9, 4.9}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Contains(tt.p); got != tt.want {
+			t.Errorf("%s: Contains(%v) = %v, want %v", tt.name, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestContainsRect(t *testing.T) {
+	r := Rect(0, 0, 10, 10)
+	tests := []struct {
+		s    Rectangle
+		want bool
+	}{
+		{Rect(2, 2, 8, 8), true},
+		{r, true},
+		{Rect(0, 0, 0, 0), true},
+		{Rect(5, 5, 11, 8), false},
+		{Rect(-1, 2, 3, 4), false},
+		{Rect(20, 20, 30, 30), false},
+		{Rect(-5, -5, 15, 15), false}, // r inside s, not the other way
+	}
+	for _, tt := range tests {
+		if got := r.ContainsRect(tt.s); got != tt.want {
+			t.Errorf("%v.ContainsRect(%v) = %v, want %v", r, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestRectangleHelpers(t *testing.T) {
+	r := Rect(1, 2, 5, 8)
+	if got := r.Max(); got != (Location{5, 8}) {
+		t.Errorf("Max = %v", got)
+	}
+	if got := r.Center(); got != (Location{3, 5}) {
+		t.Errorf("Center = %v", got)
+	}
+	if got := r.Union(Rect(-1, 4, 2, 10)); got != Rect(-1, 2, 5, 10) {
+		t.Errorf("Union = %v", got)
+	}
+	if !r.Overlaps(Rect(5, 8, 6, 9)) || r.Overlaps(Rect(5.1, 8, 6, 9)) {
+		t.Error("Overlaps wrong at the corner")
+	}
+	if got := (Location{1, 1}).Distance(Location{4, 5}); got != 5 {
+		t.Errorf("Distance = %v, want 5", got)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	// Translate comes from the embedded Location; Scale is each shape's
+	// own, and keeps the Location where it is.
+	tests := []struct {
+		name  string
+		shape interface {
+			Shape
+			Transformer
+		}
+		dx, dy float64
+		f      float64
+		bounds Rectangle
+		area   float64
+	}{
+		{"rectangle", &Rectangle{Width: 4, Height: 2, Location: Location{1, 1}}, 10, -1, 2, Rect(11, 0, 19, 4), 32},
+		{"circle", &Circle{Radius: 1, Location: Location{0, 0}}, 3, 4, 3, Rect(0, 1, 6, 7), 9 * math.Pi},
+		{"polygon", ptrPolygon(NewPolygon(Location{1, 1}, Location{3, 1}, Location{1, 4})), -1, -1, 0.5, Rect(0, 0, 1, 1.5), 0.75},
+		{"shrink to nothing", &Rectangle{Width: 4, Height: 2}, 0, 0, 0, Rectangle{}, 0},
+	}
+	for _, tt := range tests {
+		tt.shape.Translate(tt.dx, tt.dy)
+		tt.shape.Scale(tt.f)
+		if got := tt.shape.Bounds(); !nearRect(got, tt.bounds) {
+			t.Errorf("%s: Bounds = %v, want %v", tt.name, got, tt.bounds)
+		}
+		if got := tt.shape.Area(); !near(got, tt.area) {
+			t.Errorf("%s: Area = %v, want %v", tt.name, got, tt.area)
+		}
+	}
+
+	// Through the embedded field directly, as the package doc shows.
+	r := Rectangle{Width: 10, Height: 4, Location: Location{X: 199, Y: 29}}
+	r.Translate(1, -1)
+	if r.Location != (Location{200, 28}) || r.Width != 10 {
+		t.Errorf("after Translate r = %v", r)
+	}
+	r.Location.Translate(-200, -28)
+	if r.Location != (Location{}) {
+		t.Errorf("after Location.Translate r = %v", r)
+	}
+}
+
+func ptrPolygon(p Polygon) *Polygon { return &p }
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		s    Shape
+		want string
+	}{
+		{Rect(1, 2, 4, 6), "Rectangle 3x4 at (1, 2)"},
+		{Circle{Radius: 1.5, Location: Location{-1, 0}}, "Circle r=1.5 at (-1, 0)"},
+		{NewPolygon(Location{0, 0}, Location{1, 0}, Location{0, 1}), "Polygon [(0, 0) (1, 0) (0, 1)]"},
+	}
+	for _, tt := range tests {
+		if got := tt.s.(interface{ String() string }).String(); got != tt.want {
+			t.Errorf("String = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/learning-go-book/geometry
+
+go 1.18
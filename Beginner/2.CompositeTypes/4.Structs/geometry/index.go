@@ -0,0 +1,378 @@
+package geometry
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Index is a quadtree of rectangles, each with a value, answering "what
+// overlaps this box" and "what is nearest this point" without looking at
+// every rectangle. The zero value is not usable; call NewIndex.
+//
+// It is a loose quadtree: each node covers a quarter of its parent,
+// stretched by half its width and height on every side. A rectangle goes
+// to the child holding its centre, as long as it fits in the stretched
+// bounds, so small rectangles that straddle a midline still move down
+// the tree instead of piling up near the root. The bounds given to
+// NewIndex are only a starting point: inserting a rectangle outside them
+// grows the tree upwards.
+type Index[T comparable] struct {
+	root    *quadNode[T]
+	outside []Entry[T]
+	n       int
+}
+
+// Entry is a rectangle stored in an Index and its value.
+type Entry[T comparable] struct {
+	Rect  Rectangle
+	Value T
+}
+
+const (
+	maxNodeEntries = 8  // a node splits when it holds more than this
+	maxDepth       = 16 // nodes this deep never split
+)
+
+type quadNode[T comparable] struct {
+	bounds   Rectangle // the quarter of the parent this node covers
+	loose    Rectangle // bounds stretched; every entry below fits in it
+	entries  []Entry[T]
+	children *[4]quadNode[T] // nil for a leaf
+}
+
+func newQuadNode[T comparable](bounds Rectangle) quadNode[T] {
+	loose := bounds
+	loose.Translate(-bounds.Width/2, -bounds.Height/2)
+	loose.Scale(2)
+	return quadNode[T]{bounds: bounds, loose: loose}
+}
+
+// NewIndex returns an empty Index whose root covers bounds. Choosing
+// bounds close to where the rectangles will be saves growing the tree.
+// bounds must have a positive width and height.
+func NewIndex[T comparable](bounds Rectangle) *Index[T] {
+	root := newQuadNode[T](bounds)
+	return &Index[T]{root: &root}
+}
+
+// Len returns the number of entries in idx.
+func (idx *Index[T]) Len() int { return idx.n }
+
+// Insert adds r with value v. The same rectangle and value may be added
+// more than once.
+func (idx *Index[T]) Insert(r Rectangle, v T) {
+	idx.n++
+	e := Entry[T]{Rect: r, Value: v}
+	if !idx.grow(r) {
+		idx.outside = append(idx.outside, e)
+		return
+	}
+	idx.root.insert(e, 0)
+}
+
+// maxGrow limits how many times grow doubles the root for one rectangle.
+const maxGrow = 64
+
+// grow doubles the root until it covers the centre of r and its loose
+// bounds contain all of r, making the old root the quarter of the new one
+// furthest from r. Growing only until the loose bounds contain r would
+// leave r stuck in the root, as r's centre would be outside all of its
+// children. It reports false, and
+// leaves r for the outside list, if that does not work: r is not finite,
+// the root has no area to double, or r is too far away. The root is then
+// left as it was, rather than grown towards a rectangle it never holds.
+func (idx *Index[T]) grow(r Rectangle) bool {
+	if !finite(r) {
+		return false
+	}
+	old := idx.root
+	for i := 0; i < maxGrow && !idx.root.covers(r); i++ {
+		b := idx.root.bounds
+		if b.Width <= 0 || b.Height <= 0 {
+			break
+		}
+		nb := Rectangle{Width: 2 * b.Width, Height: 2 * b.Height, Location: b.Location}
+		slot := 0
+		if c := r.Center(); c.X < b.X {
+			nb.X -= b.Width
+			slot++
+		}
+		if c := r.Center(); c.Y < b.Y {
+			nb.Y -= b.Height
+			slot += 2
+		}
+		root := newQuadNode[T](nb)
+		root.split(0)
+		root.children[slot] = *idx.root
+		idx.root = &root
+	}
+	if !idx.root.covers(r) {
+		idx.root = old
+		return false
+	}
+	return true
+}
+
+// finite reports whether the corner and size of r are finite numbers.
+func finite(r Rectangle) bool {
+	for _, f := range [...]float64{r.X, r.Y, r.Width, r.Height} {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// covers reports whether r can be stored in n or below it.
+func (n *quadNode[T]) covers(r Rectangle) bool {
+	return n.bounds.Contains(r.Center()) && n.loose.ContainsRect(r)
+}
+
+func (n *quadNode[T]) insert(e Entry[T], depth int) {
+	if n.children != nil {
+		if c := n.childFor(e.Rect); c != nil {
+			c.insert(e, depth+1)
+			return
+		}
+	}
+	n.entries = append(n.entries, e)
+	if n.children == nil && len(n.entries) > maxNodeEntries && depth < maxDepth {
+		n.split(depth)
+	}
+}
+
+// split gives n four children and moves down the entries that fit in one.
+func (n *quadNode[T]) split(depth int) {
+	b := n.bounds
+	w, h := b.Width/2, b.Height/2
+	n.children = &[4]quadNode[T]{
+		newQuadNode[T](Rectangle{Width: w, Height: h, Location: Location{X: b.X, Y: b.Y}}),
+		newQuadNode[T](Rectangle{Width: w, Height: h, Location: Location{X: b.X + w, Y: b.Y}}),
+		newQuadNode[T](Rectangle{Width: w, Height: h, Location: Location{X: b.X, Y: b.Y + h}}),
+		newQuadNode[T](Rectangle{Width: w, Height: h, Location: Location{X: b.X + w, Y: b.Y + h}}),
+	}
+	kept := n.entries[:0]
+	for _, e := range n.entries {
+		if c := n.childFor(e.Rect); c != nil {
+			c.insert(e, depth+1)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	n.entries = kept
+}
+
+// childFor returns the child of n that r belongs in: the one whose
+// quarter holds the centre of r, if r fits in its loose bounds.
+func (n *quadNode[T]) childFor(r Rectangle) *quadNode[T] {
+	mid, c := n.bounds.Center(), r.Center()
+	i := 0
+	if c.X >= mid.X {
+		i++
+	}
+	if c.Y >= mid.Y {
+		i += 2
+	}
+	if child := &n.children[i]; child.loose.ContainsRect(r) {
+		return child
+	}
+	return nil
+}
+
+// Delete removes one entry with rectangle r and value v, reporting
+// whether there was one. Every node whose loose bounds contain r is
+// searched, rather than the one path Insert would take, so that rounding
+// at node edges cannot hide the entry.
+func (idx *Index[T]) Delete(r Rectangle, v T) bool {
+	e := Entry[T]{Rect: r, Value: v}
+	for i, o := range idx.outside {
+		if sameEntry(o, e) {
+			idx.outside = append(idx.outside[:i], idx.outside[i+1:]...)
+			idx.n--
+			return true
+		}
+	}
+	if idx.root.delete(e) {
+		idx.n--
+		return true
+	}
+	return false
+}
+
+// sameEntry reports whether a and b are equal, counting a NaN in one
+// rectangle as equal to a NaN in the same place in the other; with == an
+// entry whose rectangle has a NaN could never be deleted.
+func sameEntry[T comparable](a, b Entry[T]) bool {
+	same := func(x, y float64) bool { return x == y || (math.IsNaN(x) && math.IsNaN(y)) }
+	return a.Value == b.Value &&
+		same(a.Rect.X, b.Rect.X) && same(a.Rect.Y, b.Rect.Y) &&
+		same(a.Rect.Width, b.Rect.Width) && same(a.Rect.Height, b.Rect.Height)
+}
+
+func (n *quadNode[T]) delete(e Entry[T]) bool {
+	if !n.loose.ContainsRect(e.Rect) {
+		return false
+	}
+	for i, o := range n.entries {
+		if o == e {
+			n.entries = append(n.entries[:i], n.entries[i+1:]...)
+			return true
+		}
+	}
+	if n.children == nil {
+		return false
+	}
+	for i := range n.children {
+		if n.children[i].delete(e) {
+			n.collapse()
+			return true
+		}
+	}
+	return false
+}
+
+// collapse turns n back into a leaf once its subtree holds few enough
+// entries, so that deleting does not leave a tree of empty nodes.
+func (n *quadNode[T]) collapse() {
+	total := len(n.entries)
+	for i := range n.children {
+		c := &n.children[i]
+		if c.children != nil {
+			return
+		}
+		total += len(c.entries)
+	}
+	if total > maxNodeEntries {
+		return
+	}
+	for i := range n.children {
+		n.entries = append(n.entries, n.children[i].entries...)
+	}
+	n.children = nil
+}
+
+// Search calls fn for every entry whose rectangle overlaps q, until fn
+// returns false.
+func (idx *Index[T]) Search(q Rectangle, fn func(Entry[T]) bool) {
+	for _, e := range idx.outside {
+		if e.Rect.Overlaps(q) && !fn(e) {
+			return
+		}
+	}
+	idx.root.search(q, fn)
+}
+
+func (n *quadNode[T]) search(q Rectangle, fn func(Entry[T]) bool) bool {
+	if !n.loose.Overlaps(q) {
+		return true
+	}
+	for _, e := range n.entries {
+		if e.Rect.Overlaps(q) && !fn(e) {
+			return false
+		}
+	}
+	if n.children != nil {
+		for i := range n.children {
+			if !n.children[i].search(q, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Overlapping returns the entries whose rectangles overlap q.
+func (idx *Index[T]) Overlapping(q Rectangle) []Entry[T] {
+	var list []Entry[T]
+	idx.Search(q, func(e Entry[T]) bool {
+		list = append(list, e)
+		return true
+	})
+	return list
+}
+
+// Containing returns the entries whose rectangles contain p.
+func (idx *Index[T]) Containing(p Location) []Entry[T] {
+	return idx.Overlapping(Rectangle{Location: p})
+}
+
+// Nearest returns up to k entries in order of the distance from p to
+// their rectangles, nearest first. Rectangles containing p are at
+// distance zero.
+func (idx *Index[T]) Nearest(p Location, k int) []Entry[T] {
+	if k <= 0 {
+		return nil
+	}
+	// Best first search: the queue holds both nodes, keyed by the
+	// distance to their loose bounds, and entries. Nothing in a node is
+	// nearer than its loose bounds, so when an entry comes off the queue no entry
+	// still to be found is nearer.
+	q := &nearQueue[T]{}
+	for _, e := range idx.outside {
+		e := e
+		heap.Push(q, nearItem[T]{dist: rectDistance(e.Rect, p), entry: &e})
+	}
+	heap.Push(q, nearItem[T]{dist: rectDistance(idx.root.loose, p), node: idx.root})
+
+	var list []Entry[T]
+	for q.Len() > 0 && len(list) < k {
+		it := heap.Pop(q).(nearItem[T])
+		if it.entry != nil {
+			list = append(list, *it.entry)
+			continue
+		}
+		n := it.node
+		for i := range n.entries {
+			e := &n.entries[i]
+			heap.Push(q, nearItem[T]{dist: rectDistance(e.Rect, p), entry: e})
+		}
+		if n.children != nil {
+			for i := range n.children {
+				c := &n.children[i]
+				heap.Push(q, nearItem[T]{dist: rectDistance(c.loose, p), node: c})
+			}
+		}
+	}
+	return list
+}
+
+// rectDistance returns the distance from p to the nearest point of r.
+// A rectangle with a NaN in it, or an infinite one whose far edge is
+// Inf-Inf, has no distance to speak of; it is treated as infinitely far
+// away, since a NaN would break the ordering of the Nearest queue.
+func rectDistance(r Rectangle, p Location) float64 {
+	m := r.Max()
+	dx := math.Max(0, math.Max(r.X-p.X, p.X-m.X))
+	dy := math.Max(0, math.Max(r.Y-p.Y, p.Y-m.Y))
+	if d := math.Hypot(dx, dy); !math.IsNaN(d) {
+		return d
+	}
+	return math.Inf(1)
+}
+
+// nearItem is a node or an entry waiting in the Nearest queue.
+type nearItem[T comparable] struct {
+	dist  float64
+	node  *quadNode[T]
+	entry *Entry[T]
+}
+
+// nearQueue is a min-heap of nearItems by distance. Entries come before
+// nodes at the same distance, so that ties end the search early.
+type nearQueue[T comparable] []nearItem[T]
+
+func (q nearQueue[T]) Len() int { return len(q) }
+func (q nearQueue[T]) Less(i, j int) bool {
+	if q[i].dist != q[j].dist {
+		return q[i].dist < q[j].dist
+	}
+	return q[i].entry != nil && q[j].entry == nil
+}
+func (q nearQueue[T]) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *nearQueue[T]) Push(x any)   { *q = append(*q, x.(nearItem[T])) }
+func (q *nearQueue[T]) Pop() any {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
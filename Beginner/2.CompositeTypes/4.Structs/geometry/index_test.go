@@ -0,0 +1,331 @@
+package geometry
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// randRect returns a rectangle in [-spread, spread)², mostly small, with
+// now and then a large or degenerate one.
+func randRect(rng *rand.Rand, spread float64) Rectangle {
+	x := (rng.Float64()*2 - 1) * spread
+	y := (rng.Float64()*2 - 1) * spread
+	var w, h float64
+	switch rng.Intn(10) {
+	case 0:
+		// A point.
+	case 1:
+		w, h = rng.Float64()*spread, rng.Float64()*spread
+	default:
+		w, h = rng.Float64()*spread/50, rng.Float64()*spread/50
+	}
+	return Rectangle{Width: w, Height: h, Location: Location{X: x, Y: y}}
+}
+
+// oracle is the linear scan the Index must agree with.
+type oracle []Entry[int]
+
+func (o oracle) overlapping(q Rectangle) []int {
+	var ids []int
+	for _, e := range o {
+		if e.Rect.Overlaps(q) {
+			ids = append(ids, e.Value)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (o *oracle) delete(i int) Entry[int] {
+	e := (*o)[i]
+	(*o)[i] = (*o)[len(*o)-1]
+	*o = (*o)[:len(*o)-1]
+	return e
+}
+
+func values(es []Entry[int]) []int {
+	ids := make([]int, len(es))
+	for i, e := range es {
+		ids[i] = e.Value
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestIndexAgainstLinearScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	// The initial bounds cover only part of where rectangles go, so the
+	// tree has to grow.
+	idx := NewIndex[int](Rect(0, 0, 100, 100))
+	var all oracle
+	next := 0
+	for round := 0; round < 2000; round++ {
+		if len(all) > 0 && rng.Intn(3) == 0 {
+			e := all.delete(rng.Intn(len(all)))
+			if !idx.Delete(e.Rect, e.Value) {
+				t.Fatalf("round %d: Delete(%v, %d) found nothing", round, e.Rect, e.Value)
+			}
+		} else {
+			e := Entry[int]{Rect: randRect(rng, 1000), Value: next}
+			next++
+			idx.Insert(e.Rect, e.Value)
+			all = append(all, e)
+		}
+		if idx.Len() != len(all) {
+			t.Fatalf("round %d: Len = %d, want %d", round, idx.Len(), len(all))
+		}
+		if round%10 != 0 {
+			continue
+		}
+
+		q := randRect(rng, 1000)
+		if got, want := values(idx.Overlapping(q)), all.overlapping(q); !equalInts(got, want) {
+			t.Fatalf("round %d: Overlapping(%v) = %v, want %v", round, q, got, want)
+		}
+		p := q.Location
+		if got, want := values(idx.Containing(p)), all.overlapping(Rectangle{Location: p}); !equalInts(got, want) {
+			t.Fatalf("round %d: Containing(%v) = %v, want %v", round, p, got, want)
+		}
+		checkNearest(t, idx, all, p, 1+rng.Intn(10))
+	}
+
+	if idx.Delete(Rect(0, 0, 1, 1), -1) {
+		t.Error("Delete of an entry never inserted reported true")
+	}
+	for len(all) > 0 {
+		e := all.delete(0)
+		if !idx.Delete(e.Rect, e.Value) {
+			t.Fatalf("Delete(%v, %d) found nothing", e.Rect, e.Value)
+		}
+	}
+	if idx.Len() != 0 || len(idx.Overlapping(Rect(-2000, -2000, 2000, 2000))) != 0 {
+		t.Error("index not empty after deleting everything")
+	}
+}
+
+// checkNearest compares Nearest with sorting every entry by distance.
+// Entries at equal distances may come in any order, so only the
+// distances are compared.
+func checkNearest(t *testing.T, idx *Index[int], all oracle, p Location, k int) {
+	t.Helper()
+	want := make([]float64, len(all))
+	for i, e := range all {
+		want[i] = rectDistance(e.Rect, p)
+	}
+	sort.Float64s(want)
+	if len(want) > k {
+		want = want[:k]
+	}
+	got := idx.Nearest(p, k)
+	if len(got) != len(want) {
+		t.Fatalf("Nearest(%v, %d) returned %d entries, want %d", p, k, len(got), len(want))
+	}
+	for i, e := range got {
+		if d := rectDistance(e.Rect, p); d != want[i] {
+			t.Fatalf("Nearest(%v, %d)[%d] is at distance %g, want %g", p, k, i, d, want[i])
+		}
+	}
+}
+
+// oddRect returns a rectangle the tree cannot hold: one with a NaN or an
+// infinity in it, or one too far away to grow the root to.
+func oddRect(rng *rand.Rand) Rectangle {
+	r := randRect(rng, 1000)
+	nan, inf := math.NaN(), math.Inf(1)
+	switch rng.Intn(7) {
+	case 0:
+		r.X = nan
+	case 1:
+		r.Height = nan
+	case 2:
+		r.Width = inf
+	case 3:
+		r.X, r.Width = -inf, inf
+	case 4:
+		r.Y = -inf
+	case 5:
+		r.X = 1e300
+	default:
+		r.Y = -1e40
+	}
+	return r
+}
+
+func TestIndexNonFinite(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	start := Rect(0, 0, 100, 100)
+	idx := NewIndex[int](start)
+	var all oracle
+	next := 0
+	for round := 0; round < 1000; round++ {
+		switch {
+		case len(all) > 0 && rng.Intn(3) == 0:
+			e := all.delete(rng.Intn(len(all)))
+			if !idx.Delete(e.Rect, e.Value) {
+				t.Fatalf("round %d: Delete(%v, %d) found nothing", round, e.Rect, e.Value)
+			}
+		case rng.Intn(4) == 0:
+			bounds := idx.root.bounds
+			e := Entry[int]{Rect: oddRect(rng), Value: next}
+			next++
+			idx.Insert(e.Rect, e.Value)
+			all = append(all, e)
+			if idx.root.bounds != bounds {
+				t.Fatalf("round %d: inserting %v grew the root from %v to %v", round, e.Rect, bounds, idx.root.bounds)
+			}
+		default:
+			e := Entry[int]{Rect: randRect(rng, 1000), Value: next}
+			next++
+			idx.Insert(e.Rect, e.Value)
+			all = append(all, e)
+		}
+		if idx.Len() != len(all) {
+			t.Fatalf("round %d: Len = %d, want %d", round, idx.Len(), len(all))
+		}
+		if round%10 != 0 {
+			continue
+		}
+
+		q := randRect(rng, 1000)
+		if rng.Intn(4) == 0 {
+			q = oddRect(rng)
+		}
+		if got, want := values(idx.Overlapping(q)), all.overlapping(q); !equalInts(got, want) {
+			t.Fatalf("round %d: Overlapping(%v) = %v, want %v", round, q, got, want)
+		}
+		checkNearest(t, idx, all, randRect(rng, 1000).Location, 1+rng.Intn(len(all)+1))
+	}
+
+	for len(all) > 0 {
+		e := all.delete(0)
+		if !idx.Delete(e.Rect, e.Value) {
+			t.Fatalf("Delete(%v, %d) found nothing", e.Rect, e.Value)
+		}
+	}
+	if idx.Len() != 0 || len(idx.outside) != 0 {
+		t.Errorf("after deleting everything Len = %d with %d entries outside the tree", idx.Len(), len(idx.outside))
+	}
+}
+
+func TestIndexGrowFailureKeepsRoot(t *testing.T) {
+	idx := NewIndex[int](Rect(0, 0, 100, 100))
+	idx.Insert(Rect(10, 10, 20, 20), 1)
+	root := idx.root
+	for i, r := range []Rectangle{
+		Rect(1e300, 1e300, 1e300+1, 1e300+1),
+		{Width: math.NaN(), Height: 1},
+		{Location: Location{X: math.Inf(-1)}},
+		{Width: math.MaxFloat64, Height: math.MaxFloat64, Location: Location{X: math.MaxFloat64}},
+	} {
+		idx.Insert(r, i+2)
+		if idx.root != root {
+			t.Errorf("inserting %v replaced the root; it now covers %v", r, idx.root.bounds)
+			root = idx.root
+		}
+	}
+	if len(idx.outside) != 4 {
+		t.Errorf("%d entries outside the tree, want 4", len(idx.outside))
+	}
+	if got := values(idx.Nearest(Location{}, 2)); !equalInts(got, []int{1, 2}) {
+		t.Errorf("Nearest = %v, want 1 and then the far away 2", got)
+	}
+
+	// A rectangle the tree can reach still grows it.
+	idx.Insert(Rect(500, 500, 510, 510), 6)
+	if idx.root == root || !idx.root.covers(Rect(500, 500, 510, 510)) {
+		t.Errorf("root %v did not grow to a reachable rectangle", idx.root.bounds)
+	}
+}
+
+func TestIndexSearchStops(t *testing.T) {
+	idx := NewIndex[int](Rect(0, 0, 10, 10))
+	for i := 0; i < 100; i++ {
+		idx.Insert(Rect(0, 0, 10, 10), i)
+	}
+	calls := 0
+	idx.Search(Rect(0, 0, 10, 10), func(Entry[int]) bool {
+		calls++
+		return calls < 3
+	})
+	if calls != 3 {
+		t.Errorf("Search called fn %d times after it returned false, want 3", calls)
+	}
+}
+
+// benchEntries returns n random rectangles in [-1000, 1000)².
+func benchEntries(n int) oracle {
+	rng := rand.New(rand.NewSource(1))
+	all := make(oracle, n)
+	for i := range all {
+		all[i] = Entry[int]{Rect: randRect(rng, 1000), Value: i}
+	}
+	return all
+}
+
+func benchIndex(all oracle) *Index[int] {
+	idx := NewIndex[int](Rect(-1000, -1000, 1000, 1000))
+	for _, e := range all {
+		idx.Insert(e.Rect, e.Value)
+	}
+	return idx
+}
+
+func BenchmarkIndexInsert(b *testing.B) {
+	all := benchEntries(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchIndex(all)
+	}
+}
+
+func BenchmarkIndexOverlapping(b *testing.B) {
+	all := benchEntries(10000)
+	idx := benchIndex(all)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Overlapping(Rect(0, 0, 50, 50))
+	}
+}
+
+func BenchmarkLinearOverlapping(b *testing.B) {
+	all := benchEntries(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		all.overlapping(Rect(0, 0, 50, 50))
+	}
+}
+
+func BenchmarkIndexNearest(b *testing.B) {
+	idx := benchIndex(benchEntries(10000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Nearest(Location{X: 123, Y: -456}, 10)
+	}
+}
+
+func BenchmarkLinearNearest(b *testing.B) {
+	all := benchEntries(10000)
+	p := Location{X: 123, Y: -456}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sorted := append(oracle(nil), all...)
+		sort.Slice(sorted, func(i, j int) bool {
+			return rectDistance(sorted[i].Rect, p) < rectDistance(sorted[j].Rect, p)
+		})
+		_ = sorted[:10]
+	}
+}
@@ -0,0 +1,128 @@
+package geometry
+
+import "math"
+
+// Intersects reports whether a and b share at least one point. Shapes
+// include their edges, so shapes that only touch intersect. Shapes other
+// than Rectangle, Circle and Polygon, or pointers to them, are compared
+// by their Bounds.
+func Intersects(a, b Shape) bool {
+	if !a.Bounds().Overlaps(b.Bounds()) {
+		return false
+	}
+	a, b = deref(a), deref(b)
+	ca, aIsCircle := a.(Circle)
+	cb, bIsCircle := b.(Circle)
+	switch {
+	case aIsCircle && bIsCircle:
+		return ca.Location.Distance(cb.Location) <= ca.Radius+cb.Radius
+	case aIsCircle:
+		return circlePolygon(ca, asPolygon(b))
+	case bIsCircle:
+		return circlePolygon(cb, asPolygon(a))
+	}
+	if ra, ok := a.(Rectangle); ok {
+		if rb, ok := b.(Rectangle); ok {
+			return ra.Overlaps(rb)
+		}
+	}
+	return polygonPolygon(asPolygon(a), asPolygon(b))
+}
+
+// deref returns the value a *Rectangle, *Circle or *Polygon points to, so
+// that they are handled like the values; Scale needs the pointers, so
+// callers often have them. Other shapes are returned unchanged.
+func deref(s Shape) Shape {
+	switch p := s.(type) {
+	case *Rectangle:
+		return *p
+	case *Circle:
+		return *p
+	case *Polygon:
+		return *p
+	}
+	return s
+}
+
+// asPolygon returns s as a Polygon. A shape that is not one is
+// approximated by its Bounds.
+func asPolygon(s Shape) Polygon {
+	switch s := s.(type) {
+	case Polygon:
+		return s
+	case Rectangle:
+		return s.polygon()
+	}
+	return s.Bounds().polygon()
+}
+
+// circlePolygon reports whether c and p intersect: c's centre is inside
+// p, or one of p's edges comes within the radius of it.
+func circlePolygon(c Circle, p Polygon) bool {
+	if p.Contains(c.Location) {
+		return true
+	}
+	vs := p.Vertices()
+	for i, a := range vs {
+		if segmentDistance(c.Location, a, vs[(i+1)%len(vs)]) <= c.Radius {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonPolygon reports whether p and q intersect: two of their edges
+// cross, or one lies entirely inside the other.
+func polygonPolygon(p, q Polygon) bool {
+	pv, qv := p.Vertices(), q.Vertices()
+	if len(pv) == 0 || len(qv) == 0 {
+		return false
+	}
+	for i, a := range pv {
+		b := pv[(i+1)%len(pv)]
+		for j, c := range qv {
+			if segmentsIntersect(a, b, c, qv[(j+1)%len(qv)]) {
+				return true
+			}
+		}
+	}
+	return p.Contains(qv[0]) || q.Contains(pv[0])
+}
+
+// cross returns the z component of the cross product (b-a) x (c-a): it is
+// positive if c is to one side of the line through a and b, negative if
+// on the other and zero if on it.
+func cross(a, b, c Location) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// onSegment reports whether p lies on the segment from a to b.
+func onSegment(p, a, b Location) bool {
+	return cross(a, b, p) == 0 &&
+		p.X >= math.Min(a.X, b.X) && p.X <= math.Max(a.X, b.X) &&
+		p.Y >= math.Min(a.Y, b.Y) && p.Y <= math.Max(a.Y, b.Y)
+}
+
+// segmentsIntersect reports whether the segments a-b and c-d share a point.
+func segmentsIntersect(a, b, c, d Location) bool {
+	d1, d2 := cross(c, d, a), cross(c, d, b)
+	d3, d4 := cross(a, b, c), cross(a, b, d)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return onSegment(a, c, d) || onSegment(b, c, d) || onSegment(c, a, b) || onSegment(d, a, b)
+}
+
+// segmentDistance returns the distance from p to the nearest point of the
+// segment from a to b.
+func segmentDistance(p, a, b Location) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	lenSq := dx*dx + dy*dy
+	if lenSq == 0 {
+		return p.Distance(a)
+	}
+	t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / lenSq
+	t = math.Max(0, math.Min(1, t))
+	return p.Distance(Location{X: a.X + t*dx, Y: a.Y + t*dy})
+}
@@ -0,0 +1,76 @@
+package geometry
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestIntersects(t *testing.T) {
+	square := Rect(0, 0, 10, 10)
+	tri := NewPolygon(Location{20, 0}, Location{30, 0}, Location{20, 10})
+	tests := []struct {
+		name string
+		a, b Shape
+		want bool
+	}{
+		{"overlapping rectangles", square, Rect(5, 5, 15, 15), true},
+		{"touching rectangles", square, Rect(10, 0, 20, 10), true},
+		{"apart rectangles", square, Rect(11, 0, 20, 10), false},
+		{"circle in square", square, Circle{Radius: 1, Location: Location{5, 5}}, true},
+		// The bounds overlap at the corner but the circle does not reach it.
+		{"circle off corner", square, Circle{Radius: 2, Location: Location{11.5, 11.5}}, false},
+		{"circles", Circle{Radius: 1}, Circle{Radius: 1, Location: Location{2, 0}}, true},
+		{"triangle edge", tri, Rect(24, 4, 30, 10), true},
+		{"triangle hypotenuse miss", tri, Rect(26, 6, 30, 10), false},
+	}
+	for _, tt := range tests {
+		if got := Intersects(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: Intersects = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := Intersects(tt.b, tt.a); got != tt.want {
+			t.Errorf("%s: Intersects reversed = %v, want %v", tt.name, got, tt.want)
+		}
+		// Pointers must give the same answer as the values.
+		if got := Intersects(ptr(tt.a), ptr(tt.b)); got != tt.want {
+			t.Errorf("%s: Intersects of pointers = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// ptr returns a pointer to a copy of s.
+func ptr(s Shape) Shape {
+	switch s := s.(type) {
+	case Rectangle:
+		return &s
+	case Circle:
+		return &s
+	case Polygon:
+		return &s
+	}
+	return s
+}
+
+func TestWriteSVGPointers(t *testing.T) {
+	c := Circle{Radius: 3, Location: Location{5, 5}}
+	r := Rect(0, 0, 4, 2)
+	p := NewPolygon(Location{0, 0}, Location{4, 0}, Location{0, 4})
+	var values, pointers bytes.Buffer
+	if err := WriteSVG(&values, []Shape{c, r, p}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteSVG(&pointers, []Shape{&c, &r, &p}); err != nil {
+		t.Fatal(err)
+	}
+	if values.String() != pointers.String() {
+		t.Errorf("pointers drawn differently:\n%s\nvalues:\n%s", pointers.String(), values.String())
+	}
+	for _, el := range []string{"<circle ", "<rect ", "<polygon "} {
+		if !strings.Contains(values.String(), el) {
+			t.Errorf("no %s element in\n%s", el, values.String())
+		}
+	}
+	if strings.Contains(pointers.String(), "stroke-dasharray") {
+		t.Errorf("a pointer was drawn as its bounds:\n%s", pointers.String())
+	}
+}
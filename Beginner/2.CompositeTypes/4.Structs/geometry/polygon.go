@@ -0,0 +1,101 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// Polygon is a simple polygon. Its Points are relative to Location, so
+// translating it only moves Location, and scaling it scales the Points
+// about Location.
+type Polygon struct {
+	Points []Location
+	Location
+}
+
+// NewPolygon returns the polygon with the given corners, in absolute
+// coordinates. Its Location is the first point.
+func NewPolygon(points ...Location) Polygon {
+	p := Polygon{Points: make([]Location, len(points))}
+	if len(points) > 0 {
+		p.Location = points[0]
+	}
+	for i, pt := range points {
+		p.Points[i] = Location{X: pt.X - p.X, Y: pt.Y - p.Y}
+	}
+	return p
+}
+
+// Vertices returns the corners of p in absolute coordinates.
+func (p Polygon) Vertices() []Location {
+	vs := make([]Location, len(p.Points))
+	for i, pt := range p.Points {
+		vs[i] = Location{X: p.X + pt.X, Y: p.Y + pt.Y}
+	}
+	return vs
+}
+
+// Area uses the shoelace formula. It is only meaningful for polygons
+// whose edges do not cross.
+func (p Polygon) Area() float64 {
+	var sum float64
+	for i, a := range p.Points {
+		b := p.Points[(i+1)%len(p.Points)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return math.Abs(sum) / 2
+}
+
+func (p Polygon) Perimeter() float64 {
+	var sum float64
+	for i, a := range p.Points {
+		sum += a.Distance(p.Points[(i+1)%len(p.Points)])
+	}
+	return sum
+}
+
+func (p Polygon) Bounds() Rectangle {
+	vs := p.Vertices()
+	if len(vs) == 0 {
+		return Rectangle{Location: p.Location}
+	}
+	minX, minY, maxX, maxY := vs[0].X, vs[0].Y, vs[0].X, vs[0].Y
+	for _, v := range vs[1:] {
+		minX, maxX = math.Min(minX, v.X), math.Max(maxX, v.X)
+		minY, maxY = math.Min(minY, v.Y), math.Max(maxY, v.Y)
+	}
+	return Rect(minX, minY, maxX, maxY)
+}
+
+// Contains reports whether pt is inside p or on its edge, by counting how
+// many edges a ray from pt crosses.
+func (p Polygon) Contains(pt Location) bool {
+	vs := p.Vertices()
+	inside := false
+	for i, a := range vs {
+		b := vs[(i+1)%len(vs)]
+		if onSegment(pt, a, b) {
+			return true
+		}
+		if (a.Y > pt.Y) != (b.Y > pt.Y) &&
+			pt.X < a.X+(pt.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Scale multiplies the distance of every point of p from its Location
+// by f. It gives p a new Points slice rather than changing the old one,
+// which copies of p, and the slice passed in a Polygon literal, share.
+func (p *Polygon) Scale(f float64) {
+	pts := make([]Location, len(p.Points))
+	for i, pt := range p.Points {
+		pts[i] = Location{X: pt.X * f, Y: pt.Y * f}
+	}
+	p.Points = pts
+}
+
+func (p Polygon) String() string {
+	return fmt.Sprintf("Polygon %v", p.Vertices())
+}
@@ -0,0 +1,100 @@
+package geometry
+
+import "testing"
+
+func TestPolygonContainsConcave(t *testing.T) {
+	// An L: the 4x4 square from (0, 0) with its top right 2x2 corner
+	// (remembering Y grows downwards, the corner with small Y) cut out.
+	l := NewPolygon(Location{0, 0}, Location{2, 0}, Location{2, 2}, Location{4, 2}, Location{4, 4}, Location{0, 4})
+	// A U opening upwards, 6 wide and 4 tall, with a 2 wide notch.
+	u := NewPolygon(Location{0, 0}, Location{2, 0}, Location{2, 2}, Location{4, 2}, Location{4, 0}, Location{6, 0}, Location{6, 4}, Location{0, 4})
+	// An arrow head pointing right, with a notch at its back.
+	arrow := NewPolygon(Location{0, 0}, Location{4, 2}, Location{0, 4}, Location{1, 2})
+
+	tests := []struct {
+		name string
+		p    Polygon
+		pt   Location
+		want bool
+	}{
+		{"L arm", l, Location{1, 1}, true},
+		{"L foot", l, Location{3, 3}, true},
+		{"L cut out corner", l, Location{3, 1}, false},
+		{"L inner corner", l, Location{2, 2}, true},
+		{"L inner edge", l, Location{3, 2}, true},
+		{"L outer vertex", l, Location{4, 4}, true},
+		{"L outside", l, Location{5, 3}, false},
+
+		{"U left arm", u, Location{1, 1}, true},
+		{"U right arm", u, Location{5, 1}, true},
+		{"U notch", u, Location{3, 1}, false},
+		{"U notch level with the arms' tops", u, Location{3, 0}, false},
+		{"U base", u, Location{3, 3}, true},
+		{"U below", u, Location{3, 5}, false},
+
+		{"arrow middle", arrow, Location{2, 2}, true},
+		{"arrow notch", arrow, Location{0.5, 2}, false},
+		{"arrow notch vertex", arrow, Location{1, 2}, true},
+		{"arrow upper barb", arrow, Location{0.5, 0.75}, true},
+		{"arrow tip", arrow, Location{4, 2}, true},
+		{"arrow beyond tip", arrow, Location{4.5, 2}, false},
+
+		{"empty polygon", Polygon{}, Location{0, 0}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Contains(tt.pt); got != tt.want {
+			t.Errorf("%s: Contains(%v) = %v, want %v", tt.name, tt.pt, got, tt.want)
+		}
+		// Moving both the polygon and the point changes nothing.
+		moved := tt.p
+		moved.Translate(100, -50)
+		if got := moved.Contains(Location{tt.pt.X + 100, tt.pt.Y - 50}); got != tt.want {
+			t.Errorf("%s moved: Contains = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewPolygon(t *testing.T) {
+	p := NewPolygon(Location{5, 5}, Location{7, 5}, Location{5, 8})
+	if p.Location != (Location{5, 5}) {
+		t.Errorf("Location = %v, want the first point", p.Location)
+	}
+	want := []Location{{0, 0}, {2, 0}, {0, 3}}
+	for i, pt := range p.Points {
+		if pt != want[i] {
+			t.Errorf("Points = %v, want %v", p.Points, want)
+			break
+		}
+	}
+	vs := p.Vertices()
+	if vs[1] != (Location{7, 5}) || vs[2] != (Location{5, 8}) {
+		t.Errorf("Vertices = %v", vs)
+	}
+	if empty := NewPolygon(); empty.Location != (Location{}) || len(empty.Points) != 0 {
+		t.Errorf("NewPolygon() = %v", empty)
+	}
+}
+
+func TestPolygonScaleCopies(t *testing.T) {
+	points := []Location{{0, 0}, {2, 0}, {0, 2}}
+	p := Polygon{Points: points}
+	q := p // shares points
+
+	p.Scale(3)
+	if got := p.Bounds(); got != Rect(0, 0, 6, 6) {
+		t.Errorf("scaled Bounds = %v", got)
+	}
+	if got := q.Bounds(); got != Rect(0, 0, 2, 2) {
+		t.Errorf("Scale changed a copy: its Bounds are %v", got)
+	}
+	if points[1] != (Location{2, 0}) {
+		t.Errorf("Scale changed the caller's slice: %v", points)
+	}
+
+	// Scaling is about Location, which does not move.
+	r := NewPolygon(Location{10, 10}, Location{12, 10}, Location{10, 12})
+	r.Scale(0.5)
+	if r.Location != (Location{10, 10}) || r.Bounds() != Rect(10, 10, 11, 11) {
+		t.Errorf("after Scale(0.5) r = %v with bounds %v", r, r.Bounds())
+	}
+}
@@ -0,0 +1,67 @@
+package geometry
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SVGStyle sets how WriteSVG draws shapes.
+type SVGStyle struct {
+	Stroke      string  // outline colour, e.g. "black"
+	Fill        string  // fill colour, "none" for outlines only
+	StrokeWidth float64 // in user units
+	Margin      float64 // space left around the shapes
+}
+
+// DefaultSVGStyle draws black outlines.
+var DefaultSVGStyle = SVGStyle{Stroke: "black", Fill: "none", StrokeWidth: 1, Margin: 10}
+
+// WriteSVG writes an SVG document drawing shapes with DefaultSVGStyle.
+func WriteSVG(w io.Writer, shapes []Shape) error {
+	return DefaultSVGStyle.WriteSVG(w, shapes)
+}
+
+// WriteSVG writes an SVG document drawing shapes. The view box is fitted
+// to the shapes' bounds plus the margin. Shapes other than Rectangle,
+// Circle and Polygon, or pointers to them, are drawn as their bounds.
+func (st SVGStyle) WriteSVG(w io.Writer, shapes []Shape) error {
+	var box Rectangle
+	for i, s := range shapes {
+		if i == 0 {
+			box = s.Bounds()
+		} else {
+			box = box.Union(s.Bounds())
+		}
+	}
+	m := st.Margin
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%g %g %g %g" width="%g" height="%g">`+"\n",
+		box.X-m, box.Y-m, box.Width+2*m, box.Height+2*m, box.Width+2*m, box.Height+2*m)
+	fmt.Fprintf(bw, `<g stroke="%s" fill="%s" stroke-width="%g">`+"\n",
+		escapeAttr(st.Stroke), escapeAttr(st.Fill), st.StrokeWidth)
+	for _, s := range shapes {
+		switch s := deref(s).(type) {
+		case Rectangle:
+			fmt.Fprintf(bw, `<rect x="%g" y="%g" width="%g" height="%g"/>`+"\n", s.X, s.Y, s.Width, s.Height)
+		case Circle:
+			fmt.Fprintf(bw, `<circle cx="%g" cy="%g" r="%g"/>`+"\n", s.X, s.Y, s.Radius)
+		case Polygon:
+			var pts []string
+			for _, v := range s.Vertices() {
+				pts = append(pts, fmt.Sprintf("%g,%g", v.X, v.Y))
+			}
+			fmt.Fprintf(bw, `<polygon points="%s"/>`+"\n", strings.Join(pts, " "))
+		default:
+			b := s.Bounds()
+			fmt.Fprintf(bw, `<rect x="%g" y="%g" width="%g" height="%g" stroke-dasharray="4"/>`+"\n", b.X, b.Y, b.Width, b.Height)
+		}
+	}
+	fmt.Fprintln(bw, "</g>\n</svg>")
+	return bw.Flush()
+}
+
+var attrEscaper = strings.NewReplacer(`&`, "&amp;", `<`, "&lt;", `"`, "&quot;")
+
+func escapeAttr(s string) string { return attrEscaper.Replace(s) }
@@ -0,0 +1,119 @@
+// Command calc evaluates arithmetic expressions.
+//
+//	calc '2 + 3*4' '(1 + 2) ^ 100' '7 / 2' 'sqrt(2)' 'max(3, -1, 7)'
+//
+// The expression language is described in package calc: the operators
+// + - * / % and ^, parentheses, the constants pi and e, and the functions
+// abs, sqrt, pow, min and max, with exact integers however large they get.
+//
+// When the first argument names an operator or function, calc runs it as
+// a command on the arguments that follow, each of them an expression:
+//
+//	calc add 2 3
+//	calc ^ 2 100
+//	calc max 3 '-1' '2 * 4'
+//	calc help
+//
+// With no expressions, calc starts an interactive session instead, where
+// variables can be bound with "let x = 2^10" and :help lists the
+// commands. What is entered is kept in the file named by -history,
+// ~/.calc_history by default; -history "" keeps no file.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/learning-go-book/calc"
+	"github.com/learning-go-book/dispatch"
+)
+
+func main() {
+	historyFile := flag.String("history", defaultHistoryFile(), "file to keep the interactive `history` in")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: calc [-history file] [expression...]")
+		fmt.Fprintln(os.Stderr, "       calc <command> [arguments]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	e := calc.NewEnv()
+	cmds := e.Commands("calc", os.Stdout)
+	if isCommand(cmds, flag.Arg(0)) {
+		os.Exit(runCommand(cmds, flag.Args(), os.Stderr))
+	}
+	if flag.NArg() == 0 {
+		if err := interactive(e, *historyFile); err != nil {
+			fmt.Fprintln(os.Stderr, "calc:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	status := 0
+	for _, src := range flag.Args() {
+		v, err := e.Eval(src)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, describe(src, err))
+			status = 1
+			continue
+		}
+		fmt.Println(v)
+	}
+	os.Exit(status)
+}
+
+// isCommand reports whether arg names one of the commands in d, rather
+// than starting an expression; help counts as a command.
+func isCommand(d *dispatch.Dispatcher, arg string) bool {
+	_, ok := d.Lookup(arg)
+	return ok || arg == "help"
+}
+
+// runCommand dispatches args to d, writing errors to errOut, and returns
+// the exit status: 2 for a usage error, 1 for any other.
+func runCommand(d *dispatch.Dispatcher, args []string, errOut io.Writer) int {
+	err := d.Dispatch(args)
+	var usage *dispatch.UsageError
+	switch {
+	case errors.As(err, &usage):
+		fmt.Fprintf(errOut, "calc: %v\n%s\n", err, usage.Usage)
+		return 2
+	case err != nil:
+		fmt.Fprintln(errOut, "calc:", err)
+		return 1
+	}
+	return 0
+}
+
+// interactive runs a session on the terminal, loading and appending to
+// the history file if there is one.
+func interactive(e *calc.Env, historyFile string) error {
+	r := newREPL(e, os.Stdout)
+	r.prompt = "> "
+	if historyFile != "" {
+		h, err := loadHistory(historyFile)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(historyFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r.history, r.save = h, f
+	}
+	return r.run(os.Stdin)
+}
+
+// describe formats an error from evaluating src, pointing at where it
+// went wrong when it knows.
+func describe(src string, err error) string {
+	var pe *calc.Error
+	if errors.As(err, &pe) {
+		return pe.Pointer(src)
+	}
+	return err.Error()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/learning-go-book/calc"
+)
+
+func TestRunCommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		status   int
+		out, err string
+	}{
+		{[]string{"add", "2", "3"}, 0, "5\n", ""},
+		{[]string{"^", "2", "100"}, 0, "1267650600228229401496703205376\n", ""},
+		{[]string{"max", "3", "-1", "2 * 4"}, 0, "8\n", ""},
+		{[]string{"help", "^"}, 0, "usage: calc pow <x> <y>\n\nraise x to the power y\n\naliases: ^\n", ""},
+		{[]string{"/", "1", "0"}, 1, "", "calc: division by zero\n"},
+		{[]string{"abs", "1+"}, 1, "", "calc: 1+: column 3: unexpected end of expression\n"},
+		{[]string{"add", "1"}, 2, "", "calc: add takes 2 arguments, got 1\nusage: calc add <x> <y>\n"},
+	}
+	for _, tt := range tests {
+		var out, errOut strings.Builder
+		d := calc.NewEnv().Commands("calc", &out)
+		status := runCommand(d, tt.args, &errOut)
+		if status != tt.status || out.String() != tt.out || errOut.String() != tt.err {
+			t.Errorf("calc %s: status %d, wrote %q and %q; want %d, %q and %q",
+				strings.Join(tt.args, " "), status, out.String(), errOut.String(), tt.status, tt.out, tt.err)
+		}
+	}
+}
+
+func TestIsCommand(t *testing.T) {
+	d := calc.NewEnv().Commands("calc", nil)
+	for arg, want := range map[string]bool{
+		"add": true, "+": true, "sqrt": true, "help": true,
+		"2 + 3": false, "pi": false, "ad": false, "": false,
+	} {
+		if got := isCommand(d, arg); got != want {
+			t.Errorf("isCommand(%q) = %v, want %v", arg, got, want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/learning-go-book/calc"
+	"github.com/learning-go-book/dispatch"
+)
+
+const replHelp = `Enter an expression to evaluate it, or
+  let name = expression
+to bind a variable. The result of the last expression is in the
+variable ans. Lines starting with a colon are commands:
+`
+
+// repl is an interactive session reading lines from in and writing
+// results and errors to out.
+type repl struct {
+	env     *calc.Env
+	out     io.Writer
+	prompt  string
+	history []string
+	save    io.Writer // where new history lines are appended; may be nil
+
+	cmds *dispatch.Dispatcher
+	quit bool // set by :quit
+}
+
+// newREPL returns a session evaluating in e and writing to out.
+func newREPL(e *calc.Env, out io.Writer) *repl {
+	r := &repl{env: e, out: out, cmds: dispatch.New("")}
+	r.cmds.Out = out
+	r.cmds.MustRegister(
+		dispatch.Command{
+			Name:    ":vars",
+			Summary: "list variables",
+			Run:     func([]string) error { r.printVars(); return nil },
+		},
+		dispatch.Command{
+			Name:    ":history",
+			Aliases: []string{":h"},
+			Summary: "list what was entered",
+			Run: func([]string) error {
+				for i, h := range r.history {
+					fmt.Fprintf(r.out, "%4d  %s\n", i+1, h)
+				}
+				return nil
+			},
+		},
+		dispatch.Command{
+			Name:    ":help",
+			Aliases: []string{":?"},
+			Summary: "show this help",
+			Run: func([]string) error {
+				fmt.Fprint(r.out, replHelp+r.cmds.CommandList())
+				return nil
+			},
+		},
+		dispatch.Command{
+			Name:    ":quit",
+			Aliases: []string{":q"},
+			Summary: "leave; so does end of input",
+			Run:     func([]string) error { r.quit = true; return nil },
+		},
+	)
+	return r
+}
+
+// run reads and evaluates lines from in until it is exhausted or the
+// user quits. It returns only read errors; errors in what was entered
+// are reported to out.
+func (r *repl) run(in io.Reader) error {
+	sc := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(r.out, r.prompt)
+		if !sc.Scan() {
+			fmt.Fprintln(r.out)
+			return sc.Err()
+		}
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		r.remember(line)
+		r.handle(line)
+		if r.quit {
+			return nil
+		}
+	}
+}
+
+// remember adds line to the history.
+func (r *repl) remember(line string) {
+	r.history = append(r.history, line)
+	if r.save != nil {
+		fmt.Fprintln(r.save, line)
+	}
+}
+
+// handle runs one line. Like div60 in the notes on errors, it recovers
+// from a panic so that a bug hit by one expression does not end the
+// session.
+func (r *repl) handle(line string) {
+	defer func() {
+		if v := recover(); v != nil {
+			fmt.Fprintln(r.out, "internal error:", v)
+		}
+	}()
+
+	if strings.HasPrefix(line, ":") {
+		err := r.cmds.Dispatch(strings.Fields(line))
+		var unknown *dispatch.UnknownCommandError
+		if errors.As(err, &unknown) && len(unknown.Suggestions) == 0 {
+			fmt.Fprintf(r.out, "%v; try :help\n", err)
+		} else if err != nil {
+			fmt.Fprintln(r.out, err)
+		}
+		return
+	}
+
+	if _, v, err := r.env.Exec(line); err != nil {
+		fmt.Fprintln(r.out, describe(line, err))
+	} else {
+		fmt.Fprintln(r.out, v)
+	}
+}
+
+// printVars lists the variables in name order.
+func (r *repl) printVars() {
+	for _, name := range r.env.Vars() {
+		v, _ := r.env.Var(name)
+		fmt.Fprintf(r.out, "%s = %v\n", name, v)
+	}
+}
+
+// loadHistory returns the lines in the history file at path. A missing
+// or empty file is an empty history.
+func loadHistory(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		// Split would return one empty line.
+		return nil, nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+// defaultHistoryFile is where the history is kept unless -history says
+// otherwise: .calc_history in the home directory.
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return home + string(os.PathSeparator) + ".calc_history"
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/learning-go-book/calc"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testEnv returns the Env golden sessions run in: the usual one, plus a
+// crash function that panics, standing in for a bug in a function so
+// that a session can check that handle recovers from it.
+func testEnv() *calc.Env {
+	e := calc.NewEnv()
+	e.Register("crash", 0, func([]calc.Value) (calc.Value, error) {
+		var m map[string]calc.Value
+		m["boom"] = calc.Value{} // assignment to entry in nil map
+		return calc.Value{}, nil
+	})
+	return e
+}
+
+// TestREPLGolden feeds every testdata/*.in file to a session and compares
+// what it prints with the matching .golden file. A matching .history
+// file, if there is one, is loaded as the history of earlier sessions.
+// Run
+//
+//	go test -run REPLGolden -update
+//
+// to accept new output.
+func TestREPLGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.in"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no testdata/*.in files")
+	}
+	for _, in := range inputs {
+		name := strings.TrimSuffix(filepath.Base(in), ".in")
+		t.Run(name, func(t *testing.T) {
+			src, err := os.ReadFile(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			earlier, err := loadHistory(filepath.Join("testdata", name+".history"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out, saved bytes.Buffer
+			r := newREPL(testEnv(), &out)
+			r.prompt = "> "
+			r.history, r.save = earlier, &saved
+			if err := r.run(bytes.NewReader(src)); err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != string(want) {
+				t.Errorf("output differs from %s:\n--- got\n%s--- want\n%s", golden, out.String(), want)
+			}
+			if added := r.history[len(earlier):]; strings.Join(added, "\n")+"\n" != saved.String() {
+				t.Errorf("saved history %q does not match the lines added to history %q", saved.String(), added)
+			}
+		})
+	}
+}
+
+func TestLoadHistory(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name, content string
+		want          []string
+	}{
+		{"empty", "", nil},
+		{"one", "1 + 2\n", []string{"1 + 2"}},
+		{"unterminated", "a\nb", []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := loadHistory(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("%s: loadHistory = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	got, err := loadHistory(filepath.Join(dir, "missing"))
+	if err != nil || got != nil {
+		t.Errorf("missing file: loadHistory = %q, %v; want nil, nil", got, err)
+	}
+}
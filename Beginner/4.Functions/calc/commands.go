@@ -0,0 +1,133 @@
+package calc
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/learning-go-book/dispatch"
+)
+
+// operators names the binary operators in opMap, in the order the usage
+// text of Commands lists them.
+var operators = []struct{ name, symbol, summary string }{
+	{"add", "+", "add x and y"},
+	{"subtract", "-", "subtract y from x"},
+	{"multiply", "*", "multiply x by y"},
+	{"divide", "/", "divide x by y"},
+	{"mod", "%", "the remainder of dividing x by y"},
+	{"pow", "^", "raise x to the power y"},
+}
+
+// summaries describes the built-in functions for the usage text of
+// Commands; functions added with Register are described by their syntax.
+var summaries = map[string]string{
+	"abs":  "the absolute value of x",
+	"sqrt": "the square root of x",
+	"min":  "the smallest of the arguments",
+	"max":  "the largest of the arguments",
+}
+
+// Commands returns a Dispatcher for the program name that runs each
+// binary operator and function of e as a command, so that a program can
+// offer "add 2 3", "^ 2 100" or "max 3 -1 7" as well as expressions.
+// Operators are named as in "add" and have their symbol as an alias; a
+// function is named as it is called, and one already taken by an
+// operator, such as pow, is left to the operator. Each argument is
+// itself an expression, evaluated in e, and the result is written to out,
+// as is the text for help.
+//
+// The commands are those of e when Commands is called; functions
+// registered later are not added.
+func (e *Env) Commands(name string, out io.Writer) *dispatch.Dispatcher {
+	d := dispatch.New(name)
+	d.Out = out
+	for _, o := range operators {
+		op := opMap[o.symbol]
+		d.MustRegister(dispatch.Command{
+			Name:    o.name,
+			Aliases: []string{o.symbol},
+			Args:    []dispatch.Arg{{Name: "x"}, {Name: "y"}},
+			Summary: o.summary,
+			Run: e.command(out, func(a []Value) (Value, error) {
+				return op(a[0], a[1])
+			}),
+		})
+	}
+
+	names := make([]string, 0, len(e.funcs))
+	for n := range e.funcs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if _, ok := d.Lookup(n); ok {
+			continue
+		}
+		f := e.funcs[n]
+		args := funcArgs(f.arity)
+		summary, ok := summaries[n]
+		if !ok {
+			summary = callSyntax(n, args)
+		}
+		d.MustRegister(dispatch.Command{
+			Name:    n,
+			Args:    args,
+			Summary: summary,
+			Run:     e.command(out, f.fn),
+		})
+	}
+	return d
+}
+
+// command returns a command handler that evaluates its arguments, calls
+// fn with them and writes the result to out.
+func (e *Env) command(out io.Writer, fn func(args []Value) (Value, error)) func([]string) error {
+	return func(args []string) error {
+		vals := make([]Value, len(args))
+		for i, a := range args {
+			v, err := e.Eval(a)
+			if err != nil {
+				return fmt.Errorf("%s: %w", a, err)
+			}
+			vals[i] = v
+		}
+		v, err := fn(vals)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, v)
+		return err
+	}
+}
+
+// funcArgs describes the arguments of a function with the given arity.
+func funcArgs(arity int) []dispatch.Arg {
+	switch {
+	case arity < 0:
+		return []dispatch.Arg{{Name: "x"}, {Name: "more", Optional: true, Variadic: true}}
+	case arity == 1:
+		return []dispatch.Arg{{Name: "x"}}
+	case arity == 2:
+		return []dispatch.Arg{{Name: "x"}, {Name: "y"}}
+	}
+	args := make([]dispatch.Arg, arity)
+	for i := range args {
+		args[i] = dispatch.Arg{Name: fmt.Sprintf("x%d", i+1)}
+	}
+	return args
+}
+
+// callSyntax returns how the function name is called in an expression,
+// e.g. "hypot(x, y)".
+func callSyntax(name string, args []dispatch.Arg) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = a.Name
+		if a.Variadic {
+			parts[i] = "..."
+		}
+	}
+	return name + "(" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,117 @@
+package calc
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/learning-go-book/dispatch"
+)
+
+func TestCommands(t *testing.T) {
+	e := NewEnv()
+	e.Exec("let x = 2 ^ 10")
+	e.Register("hypot", 2, func(a []Value) (Value, error) {
+		return Float(a[0].Float64()*a[0].Float64() + a[1].Float64()*a[1].Float64()), nil
+	})
+	var out strings.Builder
+	d := e.Commands("calc", &out)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"add", "2", "3"}, "5"},
+		{[]string{"+", "2^64", "1"}, "18446744073709551617"},
+		{[]string{"subtract", "2", "3"}, "-1"},
+		{[]string{"-", "2", "3"}, "-1"},
+		{[]string{"*", "6", "7"}, "42"},
+		{[]string{"/", "7", "2"}, "3.5"},
+		{[]string{"%", "-7", "3"}, "-1"},
+		{[]string{"pow", "2", "100"}, "1267650600228229401496703205376"},
+		{[]string{"^", "x", "2"}, "1048576"}, // arguments see e's variables
+		{[]string{"abs", "-5"}, "5"},
+		{[]string{"sqrt", "16"}, "4"},
+		{[]string{"max", "3", "-1", "2 * 4"}, "8"},
+		{[]string{"min", "3"}, "3"},
+		{[]string{"hypot", "3", "4"}, "25"},
+	}
+	for _, tt := range tests {
+		out.Reset()
+		if err := d.Dispatch(tt.args); err != nil || out.String() != tt.want+"\n" {
+			t.Errorf("%q wrote %q, %v; want %s", tt.args, out.String(), err, tt.want)
+		}
+	}
+
+	// Every operator in opMap is a command, named and by its symbol.
+	for sym, op := range opMap {
+		c, ok := d.Lookup(sym)
+		if !ok {
+			t.Errorf("operator %s is not a command", sym)
+			continue
+		}
+		if c.Name == sym {
+			t.Errorf("operator %s has no name", sym)
+		}
+		out.Reset()
+		want, _ := op(Int(big.NewInt(12)), Int(big.NewInt(5)))
+		if err := d.Dispatch([]string{c.Name, "12", "5"}); err != nil || out.String() != want.String()+"\n" {
+			t.Errorf("%s 12 5 wrote %q, %v; want %v", c.Name, out.String(), err, want)
+		}
+	}
+}
+
+func TestCommandsErrors(t *testing.T) {
+	var out strings.Builder
+	d := NewEnv().Commands("calc", &out)
+
+	if err := d.Dispatch([]string{"/", "1", "0"}); err == nil || err.Error() != "division by zero" {
+		t.Errorf("/ 1 0 = %v", err)
+	}
+	if err := d.Dispatch([]string{"sqrt", "-1"}); err == nil || err.Error() != "square root of a negative number" {
+		t.Errorf("sqrt -1 = %v", err)
+	}
+	var pe *Error
+	if err := d.Dispatch([]string{"add", "1", "2 +"}); !errors.As(err, &pe) || err.Error() != "2 +: column 4: unexpected end of expression" {
+		t.Errorf("add 1 '2 +' = %v", err)
+	}
+	var usage *dispatch.UsageError
+	for _, args := range [][]string{{"add", "1"}, {"sqrt", "1", "2"}, {"max"}} {
+		if err := d.Dispatch(args); !errors.As(err, &usage) {
+			t.Errorf("%q = %v, want a UsageError", args, err)
+		}
+	}
+	var unknown *dispatch.UnknownCommandError
+	if err := d.Dispatch([]string{"pi"}); !errors.As(err, &unknown) {
+		t.Errorf("pi = %v, want an UnknownCommandError", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("failed commands wrote %q", out.String())
+	}
+}
+
+func TestCommandsUsage(t *testing.T) {
+	e := NewEnv()
+	e.Register("clamp", 3, func(a []Value) (Value, error) { return a[0], nil })
+	want := `usage: calc <command> [arguments]
+
+commands:
+  add <x> <y>           add x and y (also +)
+  subtract <x> <y>      subtract y from x (also -)
+  multiply <x> <y>      multiply x by y (also *)
+  divide <x> <y>        divide x by y (also /)
+  mod <x> <y>           the remainder of dividing x by y (also %)
+  pow <x> <y>           raise x to the power y (also ^)
+  abs <x>               the absolute value of x
+  clamp <x1> <x2> <x3>  clamp(x1, x2, x3)
+  max <x> [more...]     the largest of the arguments
+  min <x> [more...]     the smallest of the arguments
+  sqrt <x>              the square root of x
+
+Run "calc help <command>" for more about a command.
+`
+	if got := e.Commands("calc", nil).Usage(); got != want {
+		t.Errorf("Usage() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,320 @@
+// Package calc evaluates arithmetic expressions such as
+//
+//	2 + 3*4
+//	(1 + 2) ^ 100
+//	max(3, -1, 7)
+//
+// Expressions have the operators + - * / % and ^ (power, right
+// associative), unary minus, parentheses, the constants pi and e, and the
+// functions abs, sqrt, pow, min and max. Integers are exact however large
+// they get; a division that does not come out even gives a float.
+// Programs can add variables with Exec and functions with Register and
+// RegisterOp, and Commands offers the operators and functions as
+// commands of a dispatch.Dispatcher.
+//
+// It grew out of the opMap function table in noteFunctions.go; the calc
+// command in cmd/calc is a calculator built on it.
+package calc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// OpFuncType is the type of a binary operator: the same function table
+// idea as in noteFunctions.go, but working on values and returning an
+// error instead of panicking on, say, division by zero.
+type OpFuncType func(x, y Value) (Value, error)
+
+// opMap holds the binary operators of the expression language, by symbol.
+var opMap = map[string]OpFuncType{
+	"+": add,
+	"-": sub,
+	"*": mul,
+	"/": div,
+	"%": mod,
+	"^": pow,
+}
+
+// Op returns the function behind the binary operator symbol, such as "+"
+// or "^", so that it can be passed to RegisterOp.
+func Op(symbol string) (OpFuncType, bool) {
+	op, ok := opMap[symbol]
+	return op, ok
+}
+
+var errDivByZero = errors.New("division by zero")
+
+// maxPowBits limits the size of integer powers, so that 9^9^9 is an error
+// rather than an attempt to use all the memory.
+const maxPowBits = 1 << 20
+
+func add(x, y Value) (Value, error) {
+	if x.IsInt() && y.IsInt() {
+		return Int(new(big.Int).Add(x.i, y.i)), nil
+	}
+	return checkFloat(x.Float64() + y.Float64())
+}
+
+func sub(x, y Value) (Value, error) {
+	if x.IsInt() && y.IsInt() {
+		return Int(new(big.Int).Sub(x.i, y.i)), nil
+	}
+	return checkFloat(x.Float64() - y.Float64())
+}
+
+func mul(x, y Value) (Value, error) {
+	if x.IsInt() && y.IsInt() {
+		return Int(new(big.Int).Mul(x.i, y.i)), nil
+	}
+	return checkFloat(x.Float64() * y.Float64())
+}
+
+// div divides exactly when it can: 6/3 is the integer 2, but 7/2 is 3.5.
+func div(x, y Value) (Value, error) {
+	if y.Float64() == 0 {
+		return Value{}, errDivByZero
+	}
+	if x.IsInt() && y.IsInt() {
+		q, r := new(big.Int).QuoRem(x.i, y.i, new(big.Int))
+		if r.Sign() == 0 {
+			return Int(q), nil
+		}
+		f, _ := new(big.Rat).SetFrac(x.i, y.i).Float64()
+		return checkFloat(f)
+	}
+	return checkFloat(x.Float64() / y.Float64())
+}
+
+// mod is the remainder with the sign of x, like Go's %.
+func mod(x, y Value) (Value, error) {
+	if y.Float64() == 0 {
+		return Value{}, errDivByZero
+	}
+	if x.IsInt() && y.IsInt() {
+		return Int(new(big.Int).Rem(x.i, y.i)), nil
+	}
+	return checkFloat(math.Mod(x.Float64(), y.Float64()))
+}
+
+// pow raises x to the power y, exactly for integers and non-negative
+// integer exponents.
+func pow(x, y Value) (Value, error) {
+	if x.IsInt() && y.IsInt() && y.i.Sign() >= 0 {
+		// Divide rather than multiply: BitLen * y overflows for huge y.
+		if x.i.BitLen() > 1 && (!y.i.IsInt64() || y.i.Int64() > maxPowBits/int64(x.i.BitLen())) {
+			return Value{}, errors.New("result too large")
+		}
+		return Int(new(big.Int).Exp(x.i, y.i, nil)), nil
+	}
+	return checkFloat(math.Pow(x.Float64(), y.Float64()))
+}
+
+// function is a function callable from expressions.
+type function struct {
+	arity int // number of arguments, or -1 for any number but at least one
+	fn    func(args []Value) (Value, error)
+}
+
+// Env holds the variables and functions expressions can use.
+type Env struct {
+	vars  map[string]Value
+	funcs map[string]function
+}
+
+// NewEnv returns an Env with the built-in constants and functions.
+func NewEnv() *Env {
+	e := &Env{vars: map[string]Value{}, funcs: map[string]function{}}
+	e.vars["pi"] = Float(math.Pi)
+	e.vars["e"] = Float(math.E)
+
+	e.Register("abs", 1, func(a []Value) (Value, error) {
+		if a[0].IsInt() {
+			return Int(new(big.Int).Abs(a[0].i)), nil
+		}
+		return Float(math.Abs(a[0].f)), nil
+	})
+	e.Register("sqrt", 1, func(a []Value) (Value, error) {
+		if a[0].Float64() < 0 {
+			return Value{}, errors.New("square root of a negative number")
+		}
+		return checkFloat(math.Sqrt(a[0].Float64()))
+	})
+
+	e.RegisterOp("pow", pow)
+	e.Register("min", -1, func(a []Value) (Value, error) {
+		return pick(a, -1), nil
+	})
+	e.Register("max", -1, func(a []Value) (Value, error) {
+		return pick(a, 1), nil
+	})
+	return e
+}
+
+// Register makes fn callable as name. arity is the number of arguments
+// fn takes, or -1 for a variadic function taking at least one. fn is
+// only called with the right number of arguments.
+func (e *Env) Register(name string, arity int, fn func(args []Value) (Value, error)) {
+	e.funcs[name] = function{arity: arity, fn: fn}
+}
+
+// RegisterOp makes a binary operator, one from Op or any other
+// OpFuncType, callable as a two argument function.
+func (e *Env) RegisterOp(name string, op OpFuncType) {
+	e.Register(name, 2, func(a []Value) (Value, error) {
+		return op(a[0], a[1])
+	})
+}
+
+// pick returns the smallest of vals if sign is -1, the largest if it is 1.
+func pick(vals []Value, sign int) Value {
+	best := vals[0]
+	for _, v := range vals[1:] {
+		if compare(v, best) == sign {
+			best = v
+		}
+	}
+	return best
+}
+
+// compare returns -1, 0 or 1 as x is less than, equal to or greater than y.
+func compare(x, y Value) int {
+	if x.IsInt() && y.IsInt() {
+		return x.i.Cmp(y.i)
+	}
+	switch a, b := x.Float64(), y.Float64(); {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// eval evaluates n. Errors are *Error values pointing at the part of the
+// expression that failed.
+func (e *Env) eval(n node) (Value, error) {
+	switch n := n.(type) {
+	case *numberNode:
+		v, err := parseNumber(n.text)
+		if err != nil {
+			return Value{}, errorf(n.pos, "%v", err)
+		}
+		return v, nil
+
+	case *varNode:
+		v, ok := e.vars[n.name]
+		if !ok {
+			return Value{}, errorf(n.pos, "undefined variable %s", n.name)
+		}
+		return v, nil
+
+	case *unaryNode:
+		x, err := e.eval(n.x)
+		if err != nil || n.op == "+" {
+			return x, err
+		}
+		return sub(Int(new(big.Int)), x)
+
+	case *binaryNode:
+		x, err := e.eval(n.x)
+		if err != nil {
+			return Value{}, err
+		}
+		y, err := e.eval(n.y)
+		if err != nil {
+			return Value{}, err
+		}
+		v, err := opMap[n.op](x, y)
+		if err != nil {
+			return Value{}, errorf(n.pos, "%v", err)
+		}
+		return v, nil
+
+	case *callNode:
+		f, ok := e.funcs[n.name]
+		if !ok {
+			return Value{}, errorf(n.pos, "undefined function %s", n.name)
+		}
+		if (f.arity >= 0 && len(n.args) != f.arity) || (f.arity < 0 && len(n.args) == 0) {
+			return Value{}, errorf(n.pos, "%s takes %s, got %d", n.name, plural(f.arity), len(n.args))
+		}
+		args := make([]Value, len(n.args))
+		for i, a := range n.args {
+			v, err := e.eval(a)
+			if err != nil {
+				return Value{}, err
+			}
+			args[i] = v
+		}
+		v, err := f.fn(args)
+		if err != nil {
+			return Value{}, errorf(n.pos, "%s: %v", n.name, err)
+		}
+		return v, nil
+	}
+	panic(fmt.Sprintf("calc: unexpected node %T", n))
+}
+
+// plural describes an arity for error messages.
+func plural(arity int) string {
+	switch arity {
+	case -1:
+		return "at least 1 argument"
+	case 1:
+		return "1 argument"
+	}
+	return fmt.Sprintf("%d arguments", arity)
+}
+
+// Eval parses and evaluates the expression src. Errors in src are
+// *Error values.
+func (e *Env) Eval(src string) (Value, error) {
+	n, err := parse(src)
+	if err != nil {
+		return Value{}, locate(src, err)
+	}
+	v, err := e.eval(n)
+	return v, locate(src, err)
+}
+
+// Exec runs one statement: either an expression, whose value is also
+// bound to ans, or a binding
+//
+//	let name = expression
+//
+// It returns the name the value was bound to.
+func (e *Env) Exec(src string) (string, Value, error) {
+	name, n, err := parseStatement(src)
+	if err != nil {
+		return "", Value{}, locate(src, err)
+	}
+	v, err := e.eval(n)
+	if err != nil {
+		return "", Value{}, locate(src, err)
+	}
+	if name == "" {
+		name = "ans"
+	}
+	e.vars[name] = v
+	return name, v, nil
+}
+
+// Var returns the value of the variable name.
+func (e *Env) Var(name string) (Value, bool) {
+	v, ok := e.vars[name]
+	return v, ok
+}
+
+// Vars returns the names of the variables in e, sorted.
+func (e *Env) Vars() []string {
+	names := make([]string, 0, len(e.vars))
+	for name := range e.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,269 @@
+package calc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvalString(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"1 + 2 * 3", "7"},
+		{"(1 + 2) * 3", "9"},
+		{"2 ^ 10", "1024"},
+		{"2 ^ 100", "1267650600228229401496703205376"},
+		{"(-1) ^ 4611686018427387904", "1"},
+		{"0 ^ 4611686018427387904", "0"},
+		{"7 / 2", "3.5"},
+		{"6 / 3", "2"},
+		{"2 ^ 3 ^ 2", "512"},
+		{"2 ^ -1", "0.5"},
+
+		// Unary minus binds less tightly than ^ but more than *.
+		{"-2", "-2"},
+		{"--2", "2"},
+		{"+-+2", "-2"},
+		{"-2 ^ 2", "-4"},
+		{"(-2) ^ 2", "4"},
+		{"2 * -3", "-6"},
+		{"-(1 + 2) * 3", "-9"},
+		{"-1.5", "-1.5"},
+
+		// % keeps the sign of the left operand, like Go's %.
+		{"7 % 3", "1"},
+		{"-7 % 3", "-1"},
+		{"7 % -3", "1"},
+		{"2 ^ 100 % 7", "2"},
+		{"7.5 % 2", "1.5"},
+		{"1 + 7 % 4 * 2", "7"},
+
+		// Floats.
+		{"1.5 + 1.5", "3"},
+		{".5 * 4", "2"},
+		{"1e3", "1000"},
+		{"1E-3 * 2", "0.002"},
+		{"2.5e+1", "25"},
+		{"0.1 + 0.2", "0.30000000000000004"},
+		{"pi", "3.141592653589793"},
+		{"2 * e", "5.43656365691809"},
+
+		// Function calls.
+		{"abs(-3)", "3"},
+		{"abs(-2.5)", "2.5"},
+		{"sqrt(16)", "4"},
+		{"sqrt(2)", "1.4142135623730951"},
+		{"pow(2, 10)", "1024"},
+		{"pow(4, 0.5)", "2"},
+		{"min(3, -1, 7)", "-1"},
+		{"max(3, -1, 7)", "7"},
+		{"max(1, 2.5)", "2.5"},
+		{"max(2 ^ 70, 1e20)", "1180591620717411303424"},
+		{"min(5)", "5"},
+		{"abs(min(-3, 2)) + max(1, sqrt(9))", "6"},
+	}
+	for _, tt := range tests {
+		v, err := NewEnv().Eval(tt.src)
+		if err != nil {
+			t.Errorf("%s: %v", tt.src, err)
+			continue
+		}
+		if got := v.String(); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		col int // rune column, counting from 1
+		msg string
+	}{
+		// From the tokenizer.
+		{"2 $ 3", 3, `unexpected character '$'`},
+		{"2 # 3", 3, `unexpected character '#'`},
+		{"1 + é$", 6, `unexpected character '$'`},
+		{"⁴", 1, `unexpected character '⁴'`},
+		// From the parser.
+		{"2 + * 3", 5, `unexpected "*"`},
+		{"(1 + 2", 7, `expected ")" to close "(" at column 1`},
+		{"1 2", 3, `unexpected "2"`},
+		{"", 1, "unexpected end of expression"},
+		{"max(1 2)", 7, `expected "," or ")" in call to max`},
+		{"let x = 1", 5, `unexpected "x"`}, // Eval takes no bindings
+		// From evaluation.
+		{"1 / 0", 3, "division by zero"},
+		{"5 % 0", 3, "division by zero"},
+		{"1.5 / 0", 5, "division by zero"},
+		{"y + 1", 1, "undefined variable y"},
+		{"sqrt(4) + r", 11, "undefined variable r"},
+		{"f(1)", 1, "undefined function f"},
+		{"sqrt(1, 2)", 1, "sqrt takes 1 argument, got 2"},
+		{"pow(1)", 1, "pow takes 2 arguments, got 1"},
+		{"max()", 1, "max takes at least 1 argument, got 0"},
+		{"sqrt(-1)", 1, "sqrt: square root of a negative number"},
+		{"1e308 * 10", 7, "result out of range"},
+		{"1e999", 1, "number out of range"},
+	}
+	for _, tt := range tests {
+		_, err := NewEnv().Eval(tt.src)
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Errorf("%q: got %v, want an *Error", tt.src, err)
+			continue
+		}
+		if e.Col != tt.col || e.Msg != tt.msg {
+			t.Errorf("%q: got column %d %q, want column %d %q", tt.src, e.Col, e.Msg, tt.col, tt.msg)
+		}
+		if want := fmt.Sprintf("column %d: %s", tt.col, tt.msg); err.Error() != want {
+			t.Errorf("%q: Error() = %q, want %q", tt.src, err.Error(), want)
+		}
+	}
+}
+
+func TestErrorPointer(t *testing.T) {
+	// Columns count runes, so the caret lines up under multi-byte text.
+	src := "é + π * r"
+	_, err := NewEnv().Eval(src)
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("got %v, want an *Error", err)
+	}
+	if e.Error() != "column 1: undefined variable é" {
+		t.Errorf("Error() = %q", e.Error())
+	}
+	if _, err = NewEnv().Eval("π + 1 $"); err.Error() != "column 7: unexpected character '$'" {
+		t.Errorf("Error() = %q, want column 7", err.Error())
+	}
+	want := "π + 1 $\n      ^ unexpected character '$'"
+	if got := err.(*Error).Pointer("π + 1 $"); got != want {
+		t.Errorf("Pointer =\n%s\nwant\n%s", got, want)
+	}
+	env := NewEnv()
+	if _, _, err := env.Exec("let π = pi"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = env.Eval("π * 2 / 0"); err == nil || err.Error() != "column 7: division by zero" {
+		t.Errorf("π * 2 / 0: got %v, want column 7", err)
+	}
+}
+
+func TestExec(t *testing.T) {
+	e := NewEnv()
+	steps := []struct {
+		src, name, want string
+	}{
+		{"let x = 2 ^ 10", "x", "1024"},
+		{"x + 1", "ans", "1025"},
+		{"ans * 2", "ans", "2050"},
+		{"let y = x / 3", "y", "341.3333333333333"},
+		{"let x = -x", "x", "-1024"},
+	}
+	for _, s := range steps {
+		name, v, err := e.Exec(s.src)
+		if err != nil || name != s.name || v.String() != s.want {
+			t.Errorf("Exec(%q) = %s, %v, %v; want %s, %s", s.src, name, v, err, s.name, s.want)
+		}
+		if got, ok := e.Var(s.name); !ok || got.String() != s.want {
+			t.Errorf("after %q, %s = %v, %v", s.src, s.name, got, ok)
+		}
+	}
+	if got, want := strings.Join(e.Vars(), " "), "ans e pi x y"; got != want {
+		t.Errorf("Vars = %s, want %s", got, want)
+	}
+
+	for _, src := range []string{"let = 3", "let x 3", "let x = ", "let 2 = 3", "let z = q"} {
+		if _, _, err := e.Exec(src); err == nil {
+			t.Errorf("Exec(%q) succeeded", src)
+		}
+	}
+	if _, ok := e.Var("z"); ok {
+		t.Error("a failed let bound z")
+	}
+	if v, _ := e.Var("ans"); v.String() != "2050" {
+		t.Errorf("a failed statement changed ans to %v", v)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	e := NewEnv()
+	e.Register("double", 1, func(a []Value) (Value, error) {
+		if a[0].IsInt() {
+			return Int(new(big.Int).Lsh(a[0].BigInt(), 1)), nil
+		}
+		return Float(2 * a[0].Float64()), nil
+	})
+	e.Register("fail", 0, func([]Value) (Value, error) {
+		return Value{}, errors.New("always fails")
+	})
+	mod, ok := Op("%")
+	if !ok {
+		t.Fatal(`Op("%") not found`)
+	}
+	e.RegisterOp("mod", mod)
+	if _, ok := Op("&"); ok {
+		t.Error(`Op("&") found`)
+	}
+
+	tests := []struct{ src, want string }{
+		{"double(21)", "42"},
+		{"double(2 ^ 64)", "36893488147419103232"},
+		{"double(1.25)", "2.5"},
+		{"mod(-7, 3)", "-1"},
+		{"double(mod(7, 4))", "6"},
+	}
+	for _, tt := range tests {
+		if v, err := e.Eval(tt.src); err != nil || v.String() != tt.want {
+			t.Errorf("%s = %v, %v; want %s", tt.src, v, err, tt.want)
+		}
+	}
+	if _, err := e.Eval("1 + fail()"); err == nil || err.Error() != "column 5: fail: always fails" {
+		t.Errorf("fail() error = %v", err)
+	}
+	if _, err := e.Eval("double(1, 2)"); err == nil {
+		t.Error("double called with 2 arguments")
+	}
+}
+
+func TestValue(t *testing.T) {
+	i := big.NewInt(7)
+	v := Int(i)
+	if !v.IsInt() || v.Float64() != 7 || v.String() != "7" {
+		t.Errorf("Int(7) = %v, IsInt %v, Float64 %v", v, v.IsInt(), v.Float64())
+	}
+	v.BigInt().SetInt64(8) // a copy
+	if v.String() != "7" {
+		t.Errorf("changing BigInt changed the Value to %v", v)
+	}
+
+	f := Float(0.5)
+	if f.IsInt() || f.BigInt() != nil || f.Float64() != 0.5 || f.String() != "0.5" {
+		t.Errorf("Float(0.5) = %v, IsInt %v, BigInt %v", f, f.IsInt(), f.BigInt())
+	}
+}
+
+func TestPowTooLarge(t *testing.T) {
+	for _, src := range []string{
+		"2 ^ 4611686018427387904", // BitLen * y used to overflow int64 and pass the guard
+		"2 ^ 9223372036854775807",
+		"3 ^ 99999999999999999999",
+		"9 ^ 9 ^ 9",
+	} {
+		done := make(chan error, 1)
+		go func() {
+			_, err := NewEnv().Eval(src)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err == nil || !strings.Contains(err.Error(), "too large") {
+				t.Errorf("%s: got %v, want result too large", src, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: still computing after 5s", src)
+		}
+	}
+}
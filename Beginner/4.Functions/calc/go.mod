@@ -0,0 +1,7 @@
+module github.com/learning-go-book/calc
+
+go 1.21
+
+require github.com/learning-go-book/dispatch v0.0.0
+
+replace github.com/learning-go-book/dispatch => ../../2.CompositeTypes/3.Maps/dispatch
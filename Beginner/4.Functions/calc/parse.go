@@ -0,0 +1,213 @@
+package calc
+
+// The parser turns tokens into a tree of nodes using precedence
+// climbing: each binary operator has a precedence, and parseBinary only
+// takes operators binding at least as tightly as the level it was asked for.
+
+// node is an element of the syntax tree.
+type node interface {
+	position() int
+}
+
+type (
+	numberNode struct {
+		pos  int
+		text string
+	}
+	varNode struct {
+		pos  int
+		name string
+	}
+	unaryNode struct {
+		pos int
+		op  string
+		x   node
+	}
+	binaryNode struct {
+		pos  int // position of the operator
+		op   string
+		x, y node
+	}
+	callNode struct {
+		pos  int
+		name string
+		args []node
+	}
+)
+
+func (n *numberNode) position() int { return n.pos }
+func (n *varNode) position() int    { return n.pos }
+func (n *unaryNode) position() int  { return n.pos }
+func (n *binaryNode) position() int { return n.pos }
+func (n *callNode) position() int   { return n.pos }
+
+// binaryOp describes how a binary operator binds.
+type binaryOp struct {
+	prec       int
+	rightAssoc bool
+}
+
+var binaryOps = map[string]binaryOp{
+	"+": {1, false},
+	"-": {1, false},
+	"*": {2, false},
+	"/": {2, false},
+	"%": {2, false},
+	"^": {4, true}, // 2^3^2 is 2^(3^2)
+}
+
+// unaryPrec sits between * and ^, so -2^2 is -(2^2) and 2*-3 works.
+const unaryPrec = 3
+
+// parser holds the state of parsing one expression.
+type parser struct {
+	toks []token
+	i    int
+}
+
+// parse parses a complete expression.
+func parse(src string) (node, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	return (&parser{toks: toks}).parseExpr()
+}
+
+// parseStatement parses either an expression or a binding
+//
+//	let name = expression
+//
+// returning the name, which is empty for a plain expression.
+func parseStatement(src string) (string, node, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return "", nil, err
+	}
+	p := &parser{toks: toks}
+	if t := p.peek(); t.kind != tokIdent || t.text != "let" {
+		n, err := p.parseExpr()
+		return "", n, err
+	}
+	p.next()
+	name := p.next()
+	if name.kind != tokIdent {
+		return "", nil, errorf(name.pos, "expected a variable name after let")
+	}
+	if eq := p.next(); eq.kind != tokOp || eq.text != "=" {
+		return "", nil, errorf(eq.pos, "expected \"=\" after let %s", name.text)
+	}
+	n, err := p.parseExpr()
+	return name.text, n, err
+}
+
+// parseExpr parses the rest of the tokens as one expression.
+func (p *parser) parseExpr() (node, error) {
+	n, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errorf(t.pos, "unexpected %q", t.text)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// parseBinary parses operands joined by operators of precedence minPrec
+// or higher.
+func (p *parser) parseBinary(minPrec int) (node, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		op, ok := binaryOps[t.text]
+		if t.kind != tokOp || !ok || op.prec < minPrec {
+			return x, nil
+		}
+		p.next()
+		nextMin := op.prec + 1
+		if op.rightAssoc {
+			nextMin = op.prec
+		}
+		y, err := p.parseBinary(nextMin)
+		if err != nil {
+			return nil, err
+		}
+		x = &binaryNode{pos: t.pos, op: t.text, x: x, y: y}
+	}
+}
+
+// parseUnary parses an optionally negated operand.
+func (p *parser) parseUnary() (node, error) {
+	if t := p.peek(); t.kind == tokOp && (t.text == "-" || t.text == "+") {
+		p.next()
+		x, err := p.parseBinary(unaryPrec)
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{pos: t.pos, op: t.text, x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a number, a variable, a function call or an
+// expression in parentheses.
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &numberNode{pos: t.pos, text: t.text}, nil
+
+	case tokIdent:
+		if p.peek().kind != tokLParen {
+			return &varNode{pos: t.pos, name: t.text}, nil
+		}
+		p.next()
+		call := &callNode{pos: t.pos, name: t.text}
+		if p.peek().kind == tokRParen {
+			p.next()
+			return call, nil
+		}
+		for {
+			arg, err := p.parseBinary(1)
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			switch sep := p.next(); sep.kind {
+			case tokComma:
+				continue
+			case tokRParen:
+				return call, nil
+			default:
+				return nil, errorf(sep.pos, "expected \",\" or \")\" in call to %s", t.text)
+			}
+		}
+
+	case tokLParen:
+		x, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+		if r := p.next(); r.kind != tokRParen {
+			return nil, errorf(r.pos, "expected \")\" to close \"(\" at column %d", t.pos+1)
+		}
+		return x, nil
+
+	case tokEOF:
+		return nil, errorf(t.pos, "unexpected end of expression")
+	}
+	return nil, errorf(t.pos, "unexpected %q", t.text)
+}
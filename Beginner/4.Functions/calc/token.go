@@ -0,0 +1,142 @@
+package calc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind says what sort of token a token is.
+type tokenKind int
+
+const (
+	tokEOF    tokenKind = iota
+	tokNumber           // 42, 3.14, 1e9
+	tokIdent            // x, sqrt
+	tokOp               // + - * / % ^ =
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+// token is one lexical element of an expression.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // byte offset in the source, for error messages
+}
+
+// Error is an error at a position in an expression.
+type Error struct {
+	Pos int // byte offset in the expression
+	Col int // column in runes, counting from 1
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Col, e.Msg)
+}
+
+// Pointer returns src with a line below it pointing at the error:
+//
+//	2 + * 3
+//	    ^ unexpected "*"
+func (e *Error) Pointer(src string) string {
+	return src + "\n" + strings.Repeat(" ", column(src, e.Pos)-1) + "^ " + e.Msg
+}
+
+// errorf returns an *Error at pos. Its column is filled in by locate
+// once the error reaches Eval or Exec, which know the source.
+func errorf(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// locate sets the column of err, if it is an *Error, from src.
+func locate(src string, err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		e.Col = column(src, e.Pos)
+	}
+	return err
+}
+
+// column returns the column in runes, counting from 1, of the byte offset
+// pos in src, so that an error after "é" is not reported a column late.
+func column(src string, pos int) int {
+	return utf8.RuneCountInString(src[:min(pos, len(src))]) + 1
+}
+
+// tokenize splits src into tokens, ending with a tokEOF token.
+func tokenize(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			continue
+		case isDigit(src[i]) || (src[i] == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			i = scanNumber(src, i)
+			toks = append(toks, token{tokNumber, src[start:i], start})
+			continue
+		case r == '_' || unicode.IsLetter(r):
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			toks = append(toks, token{tokIdent, src[start:i], start})
+			continue
+		}
+
+		i += size
+		switch r {
+		case '(':
+			toks = append(toks, token{tokLParen, "(", start})
+		case ')':
+			toks = append(toks, token{tokRParen, ")", start})
+		case ',':
+			toks = append(toks, token{tokComma, ",", start})
+		case '+', '-', '*', '/', '%', '^', '=':
+			toks = append(toks, token{tokOp, string(r), start})
+		default:
+			return nil, errorf(start, "unexpected character %q", r)
+		}
+	}
+	return append(toks, token{tokEOF, "", len(src)}), nil
+}
+
+// scanNumber returns the end of the number starting at src[i]:
+// digits, an optional fraction and an optional exponent.
+func scanNumber(src string, i int) int {
+	for i < len(src) && isDigit(src[i]) {
+		i++
+	}
+	if i < len(src) && src[i] == '.' {
+		i++
+		for i < len(src) && isDigit(src[i]) {
+			i++
+		}
+	}
+	if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		j := i + 1
+		if j < len(src) && (src[j] == '+' || src[j] == '-') {
+			j++
+		}
+		if j < len(src) && isDigit(src[j]) {
+			for j < len(src) && isDigit(src[j]) {
+				j++
+			}
+			i = j
+		}
+	}
+	return i
+}
+
+func isDigit(b byte) bool { return b >= '0' && b <= '9' }
@@ -0,0 +1,80 @@
+package calc
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Value is a number: an arbitrary precision integer while the arithmetic
+// stays exact, and a float64 once it does not (3/2, sqrt(2), 1.5).
+type Value struct {
+	i *big.Int // set for integers
+	f float64  // used when i is nil
+}
+
+// Int returns the integer i as a Value. The Value keeps i, so i must not
+// be changed afterwards.
+func Int(i *big.Int) Value { return Value{i: i} }
+
+// Float returns f as a Value.
+func Float(f float64) Value { return Value{f: f} }
+
+// IsInt reports whether v is an exact integer.
+func (v Value) IsInt() bool { return v.i != nil }
+
+// BigInt returns a copy of v's integer, or nil if v is a float.
+func (v Value) BigInt() *big.Int {
+	if v.i == nil {
+		return nil
+	}
+	return new(big.Int).Set(v.i)
+}
+
+// Float64 returns v as a float64, which may lose precision for large
+// integers.
+func (v Value) Float64() float64 {
+	if v.i != nil {
+		f, _ := new(big.Float).SetInt(v.i).Float64()
+		return f
+	}
+	return v.f
+}
+
+func (v Value) String() string {
+	if v.i != nil {
+		return v.i.String()
+	}
+	return strconv.FormatFloat(v.f, 'g', -1, 64)
+}
+
+// parseNumber converts a number token to a value. Numbers written with a
+// decimal point or exponent are floats, others are integers.
+func parseNumber(text string) (Value, error) {
+	if strings.ContainsAny(text, ".eE") {
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return Value{}, errors.New("number out of range")
+		}
+		return Float(f), nil
+	}
+	i, ok := new(big.Int).SetString(text, 10)
+	if !ok {
+		return Value{}, errors.New("malformed number")
+	}
+	return Int(i), nil
+}
+
+// checkFloat turns the infinities and NaNs that float arithmetic can
+// produce into errors.
+func checkFloat(f float64) (Value, error) {
+	switch {
+	case math.IsNaN(f):
+		return Value{}, errors.New("result is not a number")
+	case math.IsInf(f, 0):
+		return Value{}, errors.New("result out of range")
+	}
+	return Float(f), nil
+}
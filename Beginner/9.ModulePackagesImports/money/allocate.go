@@ -0,0 +1,65 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Allocate splits m into parts proportional to ratios without losing or
+// creating a single minor unit: the parts always add up to m.
+//
+// Each part first gets its share rounded towards zero; the minor units
+// left over are then handed out one at a time, in the order of the ratios.
+// Allocating $10.00 with ratios 1, 1, 1 gives $3.34, $3.33 and $3.33.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("money: Allocate needs at least one ratio")
+	}
+	total := new(big.Int)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, errors.New("money: Allocate ratios must not be negative")
+		}
+		total.Add(total, big.NewInt(r))
+	}
+	if total.Sign() == 0 {
+		return nil, errors.New("money: Allocate ratios must not all be zero")
+	}
+
+	amount := big.NewInt(m.Amount)
+	parts := make([]Money, len(ratios))
+	remainder := m.Amount
+	for i, r := range ratios {
+		share := new(big.Int).Mul(amount, big.NewInt(r))
+		share.Quo(share, total) // rounds towards zero, so |share| <= |m.Amount|
+		parts[i] = Money{Amount: share.Int64(), Currency: m.Currency}
+		remainder -= parts[i].Amount
+	}
+
+	// |remainder| is less than the number of parts with a non-zero ratio.
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i++ {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].Amount += step
+		remainder -= step
+	}
+	return parts, nil
+}
+
+// Split divides m into n parts that differ by at most one minor unit and
+// add up to m exactly.
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, errors.New("money: Split needs a positive number of parts")
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
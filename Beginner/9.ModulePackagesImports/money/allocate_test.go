@@ -0,0 +1,101 @@
+package money
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// amounts returns the amounts of ms, for comparing parts.
+func amounts(ms []Money) []int64 {
+	out := make([]int64, len(ms))
+	for i, m := range ms {
+		out[i] = m.Amount
+	}
+	return out
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		amount int64
+		ratios []int64
+		want   []int64
+	}{
+		{1000, []int64{1, 1, 1}, []int64{334, 333, 333}},
+		{-1000, []int64{1, 1, 1}, []int64{-334, -333, -333}},
+		{100, []int64{1, 2}, []int64{34, 66}},
+		{100, []int64{70, 30}, []int64{70, 30}},
+		{1, []int64{70, 30}, []int64{1, 0}},
+		{5, []int64{0, 1, 1}, []int64{0, 3, 2}},
+		{0, []int64{1, 1}, []int64{0, 0}},
+		{7, []int64{1}, []int64{7}},
+		// The shares are computed with math/big, so they do not overflow.
+		{math.MaxInt64, []int64{1, 1}, []int64{math.MaxInt64/2 + 1, math.MaxInt64 / 2}},
+		{math.MinInt64, []int64{1, 1}, []int64{math.MinInt64 / 2, math.MinInt64 / 2}},
+		{math.MaxInt64, []int64{math.MaxInt64, 1}, []int64{math.MaxInt64, 0}},
+	}
+	for _, tt := range tests {
+		parts, err := Money{tt.amount, "USD"}.Allocate(tt.ratios...)
+		if err != nil {
+			t.Errorf("Allocate(%d, %v): %v", tt.amount, tt.ratios, err)
+			continue
+		}
+		if got := amounts(parts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Allocate(%d, %v) = %v, want %v", tt.amount, tt.ratios, got, tt.want)
+		}
+		for _, p := range parts {
+			if p.Currency != "USD" {
+				t.Errorf("Allocate(%d, %v) has a part in %q", tt.amount, tt.ratios, p.Currency)
+			}
+		}
+	}
+}
+
+func TestAllocateErrors(t *testing.T) {
+	for _, ratios := range [][]int64{nil, {1, -1}, {0, 0}} {
+		if parts, err := (Money{100, "USD"}).Allocate(ratios...); err == nil {
+			t.Errorf("Allocate(%v) = %v, want an error", ratios, parts)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		m    Money
+		n    int
+		want []int64
+	}{
+		{Money{1000, "USD"}, 3, []int64{334, 333, 333}},
+		{Money{1, "USD"}, 3, []int64{1, 0, 0}},
+		{Money{-2, "USD"}, 3, []int64{-1, -1, 0}},
+		{Money{1000, "JPY"}, 3, []int64{334, 333, 333}},
+		{Money{1000, "USD"}, 1, []int64{1000}},
+	}
+	for _, tt := range tests {
+		parts, err := tt.m.Split(tt.n)
+		if err != nil {
+			t.Errorf("%v.Split(%d): %v", tt.m, tt.n, err)
+			continue
+		}
+		if got := amounts(parts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v.Split(%d) = %v, want %v", tt.m, tt.n, got, tt.want)
+		}
+	}
+
+	// $10 three ways, as in the Allocate doc.
+	parts, _ := Money{1000, "USD"}.Split(3)
+	var got []string
+	for _, p := range parts {
+		d, _ := p.Decimal()
+		got = append(got, d)
+	}
+	if want := []string{"3.34", "3.33", "3.33"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("$10 split three ways = %v, want %v", got, want)
+	}
+
+	for _, n := range []int{0, -1} {
+		if _, err := (Money{100, "USD"}).Split(n); err == nil {
+			t.Errorf("Split(%d) succeeded", n)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// RateSource provides exchange rates.
+//
+// Rate returns how many units of the currency to are worth one unit of
+// the currency from (in major units), e.g. Rate("USD", "INR") might be
+// 83.2. It returns a *NoRateError if it has no rate for the pair, and an
+// *InvalidRateError if the rate it has is not a positive number.
+type RateSource interface {
+	Rate(from, to string) (*big.Rat, error)
+}
+
+// StaticRates is a RateSource backed by a fixed table. Keys are pairs
+// written "FROM/TO" and values are decimal strings, e.g.
+//
+//	StaticRates{"USD/CAD": "1.36", "USD/EUR": "0.92"}
+//
+// If only the opposite pair is listed, its inverse is used.
+type StaticRates map[string]string
+
+// Rate implements RateSource.
+func (s StaticRates) Rate(from, to string) (*big.Rat, error) {
+	if v, ok := s[from+"/"+to]; ok {
+		return parseRate(v, from, to)
+	}
+	if v, ok := s[to+"/"+from]; ok {
+		r, err := parseRate(v, to, from)
+		if err != nil {
+			return nil, err
+		}
+		return r.Inv(r), nil
+	}
+	return nil, &NoRateError{From: from, To: to}
+}
+
+// parseRate parses a positive decimal exchange rate.
+func parseRate(v, from, to string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(v))
+	if !ok || r.Sign() <= 0 {
+		return nil, &InvalidRateError{From: from, To: to, Value: v}
+	}
+	return r, nil
+}
+
+// SampleRates are illustrative USD based rates used by Convert. They are
+// not kept up to date; use a Converter with a real RateSource for
+// anything that matters.
+var SampleRates = StaticRates{
+	"USD/CAD": "1.36",
+	"USD/EUR": "0.92",
+	"USD/INR": "83.20",
+}
+
+// Converter converts Money between currencies using rates from Rates.
+type Converter struct {
+	Rates RateSource
+}
+
+// ErrNoRates is returned by a Converter whose Rates is nil.
+var ErrNoRates = errors.New("money: Converter has no Rates")
+
+// Convert converts from into the currency to, rounding half to even to a
+// whole minor unit of the target currency. Converting to the same
+// currency returns from unchanged without consulting Rates.
+// If an error is returned, the Money instance is set to the zero value.
+func (c Converter) Convert(from Money, to string) (Money, error) {
+	return c.convert(from, to, func(f, t string) (*big.Rat, error) {
+		return c.Rates.Rate(f, t)
+	})
+}
+
+// convert does the work of Convert and ConvertOn, taking the rate from rate.
+func (c Converter) convert(from Money, to string, rate func(from, to string) (*big.Rat, error)) (Money, error) {
+	src, err := LookupCurrency(from.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	dst, err := LookupCurrency(to)
+	if err != nil {
+		return Money{}, err
+	}
+	if src.Code == dst.Code {
+		return from, nil
+	}
+	if c.Rates == nil {
+		return Money{}, ErrNoRates
+	}
+
+	rt, err := rate(src.Code, dst.Code)
+	if err != nil {
+		return Money{}, err
+	}
+	// amount is in minor units of src; scale to minor units of dst.
+	r := new(big.Rat).SetInt64(from.Amount)
+	r.Mul(r, rt)
+	r.Mul(r, pow10(dst.Exponent-src.Exponent))
+	amount, ok := roundHalfEven(r)
+	if !ok {
+		return Money{}, &OverflowError{Op: "Convert"}
+	}
+	return Money{Amount: amount, Currency: dst.Code}, nil
+}
+
+// Convert converts the value of one currency to another.
+//
+// It has two parameters: a Money instance with the value to convert,
+// and a string that represents the currency to convert to. Convert returns
+// the converted currency and any errors encountered from unknown or unconvertible
+// currencies.
+// If an error is returned, the Money instance is set to the zero value.
+//
+// Supported currencies are:
+//
+//	USD - US Dollar
+//	CAD - Canadian Dollar
+//	EUR - Euro
+//	INR - Indian Rupee
+//
+// Convert uses SampleRates, going through USD for pairs it does not list,
+// such as EUR to INR; for other rates use a Converter.
+//
+// More information on exchange rates can be found
+// at https://www.investopedia.com/terms/e/exchangerate.asp
+func Convert(from Money, to string) (Money, error) {
+	return Converter{Rates: Triangulated{Source: SampleRates, Base: "USD"}}.Convert(from, to)
+}
@@ -0,0 +1,61 @@
+package money
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Currency describes an ISO 4217 currency.
+type Currency struct {
+	Code     string `json:"code"` // ISO 4217 code, e.g. "USD"
+	Name     string `json:"name"`
+	Exponent int    `json:"exponent"` // number of minor unit digits: 2 for cents, 0 for yen
+	Symbol   string `json:"symbol"`   // symbol used when a locale does not define its own
+}
+
+// localeData is the bundled table of currencies and locales.
+//
+//go:embed localedata.json
+var localeData []byte
+
+// currencies and locales are read from localeData once, when the package
+// is loaded, and never changed afterwards.
+var currencies, locales = mustLoadLocaleData(localeData)
+
+// mustLoadLocaleData parses the bundled data file. It panics if the file
+// is broken, which can only happen if it was edited badly, since it is
+// compiled into the package.
+func mustLoadLocaleData(data []byte) (map[string]Currency, map[string]Locale) {
+	var doc struct {
+		Currencies []Currency `json:"currencies"`
+		Locales    []Locale   `json:"locales"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		panic(fmt.Sprintf("money: bad localedata.json: %v", err))
+	}
+	cs := make(map[string]Currency, len(doc.Currencies))
+	for _, c := range doc.Currencies {
+		cs[c.Code] = c
+	}
+	ls := make(map[string]Locale, len(doc.Locales))
+	for _, l := range doc.Locales {
+		if _, ok := cs[l.Currency]; !ok || len(l.Grouping) == 0 {
+			panic(fmt.Sprintf("money: bad localedata.json: locale %s", l.Tag))
+		}
+		ls[strings.ToLower(l.Tag)] = l
+	}
+	return cs, ls
+}
+
+// LookupCurrency returns the Currency for an ISO 4217 code.
+// The code is case insensitive. Unknown codes return an
+// *UnknownCurrencyError.
+func LookupCurrency(code string) (Currency, error) {
+	c, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, &UnknownCurrencyError{Code: code}
+	}
+	return c, nil
+}
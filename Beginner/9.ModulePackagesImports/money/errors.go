@@ -0,0 +1,55 @@
+package money
+
+import "fmt"
+
+// UnknownCurrencyError is returned for a currency code that is not
+// supported.
+type UnknownCurrencyError struct {
+	Code string
+}
+
+func (e *UnknownCurrencyError) Error() string {
+	return fmt.Sprintf("money: unknown currency %q", e.Code)
+}
+
+// CurrencyMismatchError is returned when two amounts in different
+// currencies are combined.
+type CurrencyMismatchError struct {
+	A, B string
+}
+
+func (e *CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("money: currency mismatch: %s and %s", e.A, e.B)
+}
+
+// NoRateError is returned by a RateSource that has no exchange rate
+// between two currencies.
+type NoRateError struct {
+	From, To string
+}
+
+func (e *NoRateError) Error() string {
+	return fmt.Sprintf("money: no exchange rate from %s to %s", e.From, e.To)
+}
+
+// InvalidRateError is returned by a RateSource whose rate for a pair is
+// not a positive decimal number, such as a mistyped StaticRates entry.
+// Unlike a *NoRateError it is not a reason to try another route.
+type InvalidRateError struct {
+	From, To string
+	Value    string
+}
+
+func (e *InvalidRateError) Error() string {
+	return fmt.Sprintf("money: invalid exchange rate %q from %s to %s", e.Value, e.From, e.To)
+}
+
+// OverflowError is returned when a result does not fit in an int64
+// number of minor units.
+type OverflowError struct {
+	Op string
+}
+
+func (e *OverflowError) Error() string {
+	return "money: overflow in " + e.Op
+}
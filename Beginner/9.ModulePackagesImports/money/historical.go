@@ -0,0 +1,76 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// HistoricalRateSource provides the exchange rates that applied on a
+// given day.
+type HistoricalRateSource interface {
+	RateSource
+	RateOn(date time.Time, from, to string) (*big.Rat, error)
+}
+
+// dateLayout is how days are written in rate files and HistoricalRates.
+const dateLayout = "2006-01-02"
+
+// HistoricalRates holds a table of rates for each day, keyed by the date
+// written as "2006-01-02". A pair missing from a day's table takes its
+// rate from the most recent earlier day that has one, so weekends and
+// holidays get Friday's rates.
+type HistoricalRates map[string]StaticRates
+
+// RateOn returns the rate that applied on date.
+func (h HistoricalRates) RateOn(date time.Time, from, to string) (*big.Rat, error) {
+	day := date.Format(dateLayout)
+	days := h.days()
+	// Index of the first day after date; the ones before it apply.
+	i := sort.SearchStrings(days, day)
+	if i < len(days) && days[i] == day {
+		i++
+	}
+	return h.latest(days[:i], from, to)
+}
+
+// Rate implements RateSource with the most recent rate for the pair.
+func (h HistoricalRates) Rate(from, to string) (*big.Rat, error) {
+	return h.latest(h.days(), from, to)
+}
+
+// latest returns the rate from the last of days that has one.
+func (h HistoricalRates) latest(days []string, from, to string) (*big.Rat, error) {
+	for i := len(days) - 1; i >= 0; i-- {
+		r, err := h[days[i]].Rate(from, to)
+		var noRate *NoRateError
+		if !errors.As(err, &noRate) {
+			return r, err
+		}
+	}
+	return nil, &NoRateError{From: from, To: to}
+}
+
+// days returns the dates in h, oldest first. Dates sort correctly as
+// strings because of their fixed width layout.
+func (h HistoricalRates) days() []string {
+	days := make([]string, 0, len(h))
+	for d := range h {
+		days = append(days, d)
+	}
+	sort.Strings(days)
+	return days
+}
+
+// ConvertOn is like Convert, but uses the rates that applied on date.
+// c.Rates must implement HistoricalRateSource.
+func (c Converter) ConvertOn(date time.Time, from Money, to string) (Money, error) {
+	hist, ok := c.Rates.(HistoricalRateSource)
+	if !ok && c.Rates != nil {
+		return Money{}, &NoRateError{From: from.Currency, To: to}
+	}
+	return c.convert(from, to, func(f, t string) (*big.Rat, error) {
+		return hist.RateOn(date, f, t)
+	})
+}
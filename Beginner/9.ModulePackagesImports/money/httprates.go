@@ -0,0 +1,98 @@
+package money
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPRates fetches rates from an HTTP API that answers
+//
+//	GET {URL}?base=USD&symbols=EUR[&date=2024-01-02]
+//
+// with a JSON document like
+//
+//	{"base": "USD", "date": "2024-01-02", "rates": {"EUR": 0.9123}}
+//
+// Every call makes a request; put a CachedRates in front of it. A
+// response for another base currency than the one asked for is rejected.
+type HTTPRates struct {
+	URL    string
+	Client *http.Client // defaults to a client with a 10 second timeout
+}
+
+var defaultHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// Rate implements RateSource with the latest rates.
+// It is RateContext with context.Background.
+func (h HTTPRates) Rate(from, to string) (*big.Rat, error) {
+	return h.RateContext(context.Background(), from, to)
+}
+
+// RateContext is Rate with a context that can cancel the request.
+func (h HTTPRates) RateContext(ctx context.Context, from, to string) (*big.Rat, error) {
+	return h.fetch(ctx, from, to, "")
+}
+
+// RateOn implements HistoricalRateSource.
+// It is RateOnContext with context.Background.
+func (h HTTPRates) RateOn(date time.Time, from, to string) (*big.Rat, error) {
+	return h.RateOnContext(context.Background(), date, from, to)
+}
+
+// RateOnContext is RateOn with a context that can cancel the request.
+func (h HTTPRates) RateOnContext(ctx context.Context, date time.Time, from, to string) (*big.Rat, error) {
+	return h.fetch(ctx, from, to, date.Format(dateLayout))
+}
+
+// fetch asks the API for the from/to rate, on date if it is not empty.
+func (h HTTPRates) fetch(ctx context.Context, from, to, date string) (*big.Rat, error) {
+	u, err := url.Parse(h.URL)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("base", from)
+	q.Set("symbols", to)
+	if date != "" {
+		q.Set("date", date)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := h.Client
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("money: rates request %s: %s", u, resp.Status)
+	}
+
+	var doc ratesDocument
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("money: rates response: %w", err)
+	}
+	if !strings.EqualFold(doc.Base, from) {
+		return nil, fmt.Errorf("money: rates response has base %q, asked for %s", doc.Base, from)
+	}
+	rate, ok := doc.Rates[to]
+	if !ok {
+		return nil, &NoRateError{From: from, To: to}
+	}
+	return parseRate(rate.String(), from, to)
+}
@@ -0,0 +1,136 @@
+package money
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LoadRatesCSV reads rates from CSV with the columns
+//
+//	date,from,to,rate
+//	2024-01-02,USD,EUR,0.9123
+//
+// A header row whose first field is "date" is skipped. The date column
+// may be left empty for rates without a date; they are stored under the
+// zero date, 0001-01-01.
+func LoadRatesCSV(r io.Reader) (HistoricalRates, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 4
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+
+	h := HistoricalRates{}
+	for first := true; ; first = false {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return h, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if first && strings.EqualFold(rec[0], "date") {
+			continue
+		}
+		if err := h.add(rec[0], rec[1], rec[2], rec[3]); err != nil {
+			// Ask the reader for the line: counting records would miss
+			// comment lines and quoted fields spanning several lines.
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("money: rates CSV line %d: %w", line, err)
+		}
+	}
+}
+
+// ratesDocument is the JSON form of a day's rates, the shape most
+// exchange rate APIs answer with:
+//
+//	{"base": "USD", "date": "2024-01-02", "rates": {"EUR": 0.9123, "INR": 83.2}}
+type ratesDocument struct {
+	Base  string                 `json:"base"`
+	Date  string                 `json:"date"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+// LoadRatesJSON reads rates from JSON: either a single ratesDocument or an
+// array of them, one per day. Numbers are kept exactly as written.
+func LoadRatesJSON(r io.Reader) (HistoricalRates, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	var docs []ratesDocument
+	if trimmed := strings.TrimSpace(string(raw)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(raw, &docs); err != nil {
+			return nil, err
+		}
+	} else {
+		var d ratesDocument
+		if err := json.Unmarshal(raw, &d); err != nil {
+			return nil, err
+		}
+		docs = append(docs, d)
+	}
+
+	h := HistoricalRates{}
+	for _, d := range docs {
+		for to, rate := range d.Rates {
+			if err := h.add(d.Date, d.Base, to, rate.String()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return h, nil
+}
+
+// LoadRatesFile reads rates from a .csv or .json file.
+func LoadRatesFile(path string) (HistoricalRates, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return LoadRatesCSV(f)
+	case ".json":
+		return LoadRatesJSON(f)
+	}
+	return nil, errors.New("money: rates file must end in .csv or .json: " + path)
+}
+
+// add stores one rate in h after checking it.
+func (h HistoricalRates) add(date, from, to, rate string) error {
+	day := time.Time{}.Format(dateLayout)
+	if date = strings.TrimSpace(date); date != "" {
+		t, err := time.Parse(dateLayout, date)
+		if err != nil {
+			return err
+		}
+		day = t.Format(dateLayout)
+	}
+	src, err := LookupCurrency(strings.TrimSpace(from))
+	if err != nil {
+		return err
+	}
+	dst, err := LookupCurrency(strings.TrimSpace(to))
+	if err != nil {
+		return err
+	}
+	if _, err := parseRate(rate, src.Code, dst.Code); err != nil {
+		return err
+	}
+	if h[day] == nil {
+		h[day] = StaticRates{}
+	}
+	h[day][src.Code+"/"+dst.Code] = strings.TrimSpace(rate)
+	return nil
+}
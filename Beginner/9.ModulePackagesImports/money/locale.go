@@ -0,0 +1,260 @@
+package money
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Locale describes how amounts are written in one locale. The available
+// locales are listed in localedata.json.
+type Locale struct {
+	Tag      string            `json:"tag"`      // BCP 47 tag, e.g. "en-IN"
+	Currency string            `json:"currency"` // assumed by Parse when no symbol is given
+	Decimal  string            `json:"decimal"`  // decimal mark
+	Group    string            `json:"group"`    // grouping separator
+	Grouping []int             `json:"grouping"` // group sizes from the right, the last one repeats: [3] or [3, 2]
+	Positive string            `json:"positive"` // pattern for amounts >= 0; ¤ is the symbol, # the number
+	Negative string            `json:"negative"` // pattern for amounts < 0
+	Symbols  map[string]string `json:"symbols"`  // currency symbols that differ from Currency.Symbol
+}
+
+// UnknownLocaleError is returned for a locale tag that is not supported.
+type UnknownLocaleError struct {
+	Tag string
+}
+
+func (e *UnknownLocaleError) Error() string {
+	return fmt.Sprintf("money: unknown locale %q", e.Tag)
+}
+
+// LookupLocale returns the Locale for a tag such as "en-IN" or "en_IN".
+func LookupLocale(tag string) (Locale, error) {
+	l, ok := locales[strings.ToLower(strings.ReplaceAll(tag, "_", "-"))]
+	if !ok {
+		return Locale{}, &UnknownLocaleError{Tag: tag}
+	}
+	return l, nil
+}
+
+// symbol returns the symbol l uses for c.
+func (l Locale) symbol(c Currency) string {
+	if s, ok := l.Symbols[c.Code]; ok {
+		return s
+	}
+	return c.Symbol
+}
+
+// Format writes m the way locale writes amounts, with the currency's
+// minor units, grouping separators and symbol placement:
+//
+//	Format(m, "en-US") // "$1,234,567.89"
+//	Format(m, "de-DE") // "1.234.567,89 $"
+//	Format(m, "en-IN") // "$12,34,567.89"
+func Format(m Money, locale string) (string, error) {
+	l, err := LookupLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	c, err := LookupCurrency(m.Currency)
+	if err != nil {
+		return "", err
+	}
+
+	dec, err := m.Decimal()
+	if err != nil {
+		return "", err
+	}
+	digits := strings.TrimPrefix(dec, "-")
+	intPart, frac, _ := strings.Cut(digits, ".")
+	number := group(intPart, l.Grouping, l.Group)
+	if frac != "" {
+		number += l.Decimal + frac
+	}
+
+	pattern := l.Positive
+	if m.Amount < 0 {
+		pattern = l.Negative
+	}
+	return strings.NewReplacer("¤", l.symbol(c), "#", number).Replace(pattern), nil
+}
+
+// group inserts sep into the digits, in groups whose sizes are given
+// from the right by sizes; the last size repeats. [3, 2] gives the Indian
+// lakh and crore grouping 1,23,45,678.
+func group(digits string, sizes []int, sep string) string {
+	var parts []string
+	for i := 0; len(digits) > 0; i++ {
+		size := sizes[len(sizes)-1]
+		if i < len(sizes) {
+			size = sizes[i]
+		}
+		if size <= 0 || size >= len(digits) {
+			parts = append(parts, digits)
+			break
+		}
+		parts = append(parts, digits[len(digits)-size:])
+		digits = digits[:len(digits)-size]
+	}
+	// parts were collected right to left.
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, sep)
+}
+
+// symbolCandidate is a piece of text Parse recognises as a currency.
+type symbolCandidate struct {
+	text string
+	code string
+}
+
+// symbolCandidates lists what can mark a currency in l: l's own
+// symbols first, then the default symbols and the ISO codes, longest
+// first so that "US$" is tried before "$".
+func (l Locale) symbolCandidates() []symbolCandidate {
+	var list []symbolCandidate
+	seen := map[string]bool{}
+	add := func(text, code string) {
+		if text != "" && !seen[text] {
+			seen[text] = true
+			list = append(list, symbolCandidate{text, code})
+		}
+	}
+	// The locale's own symbol for its currency wins over every other
+	// currency using the same symbol: "$" is CAD in en-CA.
+	if c, ok := currencies[l.Currency]; ok {
+		add(l.symbol(c), c.Code)
+	}
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		add(l.Symbols[code], code)
+	}
+	for _, code := range codes {
+		add(currencies[code].Symbol, code)
+		add(code, code)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return len(list[i].text) > len(list[j].text)
+	})
+	return list
+}
+
+// Parse reads an amount written the way locale writes it, such as
+// "₹12,34,567.89" for en-IN or "-1.234,50 €" for de-DE. The currency is
+// taken from the symbol or ISO code in s, or is the locale's currency if
+// there is none. A leading or trailing minus sign, or parentheses, make
+// the amount negative. More decimals than the currency has minor units
+// is an error rather than being rounded away.
+func Parse(s, locale string) (Money, error) {
+	l, err := LookupLocale(locale)
+	if err != nil {
+		return Money{}, err
+	}
+	bad := func(why string) (Money, error) {
+		return Money{}, fmt.Errorf("money: cannot parse %q as %s: %s", s, l.Tag, why)
+	}
+
+	// Locale data uses no-break spaces, but people type ordinary ones.
+	rest := normalizeSpace(strings.TrimSpace(s))
+	code := l.Currency
+	for _, cand := range l.symbolCandidates() {
+		if i := strings.Index(rest, normalizeSpace(cand.text)); i >= 0 {
+			code = cand.code
+			rest = rest[:i] + rest[i+len(normalizeSpace(cand.text)):]
+			break
+		}
+	}
+	c, err := LookupCurrency(code)
+	if err != nil {
+		return Money{}, err
+	}
+
+	rest = strings.TrimFunc(rest, unicode.IsSpace)
+	neg := false
+	switch {
+	case strings.HasPrefix(rest, "(") && strings.HasSuffix(rest, ")"):
+		neg, rest = true, rest[1:len(rest)-1]
+	case strings.HasPrefix(rest, "-"):
+		neg, rest = true, strings.TrimPrefix(rest, "-")
+	case strings.HasPrefix(rest, "−"): // U+2212 MINUS SIGN
+		neg, rest = true, strings.TrimPrefix(rest, "−")
+	case strings.HasSuffix(rest, "-"):
+		neg, rest = true, rest[:len(rest)-1]
+	}
+	rest = strings.TrimFunc(rest, unicode.IsSpace)
+
+	intPart, frac, hasDecimal := strings.Cut(rest, l.Decimal)
+	// Grouping separators are optional, but where they are used they must
+	// sit where Format puts them: "1,2,3" is not 123 in en-US. Spaces
+	// were all made ordinary ones above.
+	if sep := normalizeSpace(l.Group); strings.Contains(intPart, sep) {
+		groups := strings.Split(intPart, sep)
+		if !validGroups(groups, l.Grouping) {
+			return bad("misplaced grouping separator")
+		}
+		intPart = strings.Join(groups, "")
+	}
+	if intPart == "" || !allDigits(intPart) || !allDigits(frac) || (hasDecimal && frac == "") {
+		return bad("not a number")
+	}
+	if len(frac) > c.Exponent {
+		return bad(fmt.Sprintf("%s has %d decimal places", c.Code, c.Exponent))
+	}
+
+	amount := intPart
+	if frac != "" {
+		amount += "." + frac
+	}
+	if neg {
+		amount = "-" + amount
+	}
+	return FromString(amount, c.Code)
+}
+
+// validGroups reports whether groups, the digits between grouping
+// separators from left to right, have the sizes given from the right by
+// sizes, as in group. Only the leftmost group may be shorter.
+func validGroups(groups []string, sizes []int) bool {
+	for k := 0; k < len(groups); k++ {
+		g := groups[len(groups)-1-k]
+		size := sizes[len(sizes)-1]
+		if k < len(sizes) {
+			size = sizes[k]
+		}
+		if k == len(groups)-1 {
+			if len(g) == 0 || len(g) > size {
+				return false
+			}
+		} else if len(g) != size {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeSpace replaces every kind of space, such as the no-break
+// space, with an ordinary one.
+func normalizeSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// allDigits reports whether s consists of ASCII digits only.
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
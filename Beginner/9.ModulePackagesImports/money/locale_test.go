@@ -0,0 +1,123 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		m      Money
+		locale string
+		want   string
+	}{
+		{Money{123456789, "USD"}, "en-US", "$1,234,567.89"},
+		{Money{-123456789, "USD"}, "en-US", "-$1,234,567.89"},
+		{Money{123456789, "USD"}, "de-DE", "1.234.567,89 $"},
+		{Money{123456789, "INR"}, "en-IN", "₹12,34,567.89"},
+		{Money{5, "EUR"}, "fr-FR", "0,05 €"},
+		{Money{100000, "CAD"}, "en-CA", "$1,000.00"},
+		{Money{100000, "USD"}, "en-CA", "US$1,000.00"},
+		{Money{1234567, "JPY"}, "ja-JP", "￥1,234,567"},
+		{Money{1234567, "BHD"}, "en-US", "BHD1,234.567"},
+	}
+	for _, tt := range tests {
+		got, err := Format(tt.m, tt.locale)
+		if err != nil {
+			t.Errorf("Format(%v, %s): %v", tt.m, tt.locale, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Format(%v, %s) = %q, want %q", tt.m, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s, locale string
+		want      Money
+	}{
+		{"$1,234,567.89", "en-US", Money{123456789, "USD"}},
+		{"1234567.89", "en-US", Money{123456789, "USD"}},
+		{"-$12.50", "en-US", Money{-1250, "USD"}},
+		{"($12.50)", "en-US", Money{-1250, "USD"}},
+		{"−$12.50", "en-US", Money{-1250, "USD"}},
+		{"−5", "en-US", Money{-500, "USD"}},
+		{"12.50-", "en-US", Money{-1250, "USD"}},
+		{"1.234.567,89 €", "de-DE", Money{123456789, "EUR"}},
+		{"1.234,5 €", "de-DE", Money{123450, "EUR"}},
+		{"₹12,34,567.89", "en-IN", Money{123456789, "INR"}},
+		{"1,00,000", "en-IN", Money{10000000, "INR"}},
+		{"1 234,50 €", "fr-FR", Money{123450, "EUR"}},
+		{"1 234,50 €", "fr-FR", Money{123450, "EUR"}},
+		{"$5", "en-CA", Money{500, "CAD"}},
+		{"US$5", "en-CA", Money{500, "USD"}},
+		{"EUR 5", "en-US", Money{500, "EUR"}},
+		{"￥1,234", "ja-JP", Money{1234, "JPY"}},
+		{"12", "en_us", Money{1200, "USD"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.s, tt.locale)
+		if err != nil {
+			t.Errorf("Parse(%q, %s): %v", tt.s, tt.locale, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q, %s) = %v, want %v", tt.s, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct{ s, locale string }{
+		{"1,2,3", "en-US"},
+		{"12,34", "en-US"},
+		{"1,2345", "en-US"},
+		{",123", "en-US"},
+		{"1,,234", "en-US"},
+		{"1234,567.00", "en-US"},
+		{"12,34,567", "en-US"}, // Indian grouping in a Western locale
+		{"1,234,567", "en-IN"}, // and the other way round
+		{"1.5 €", "de-DE"},     // the dot is the grouping separator
+		{"$1.234", "en-US"},    // more decimals than cents
+		{"¥1.5", "ja-JP"},      // yen has no minor unit
+		{"", "en-US"},
+		{"$", "en-US"},
+		{"12.", "en-US"},
+		{"1e3", "en-US"},
+		{"abc", "en-US"},
+		{"--5", "en-US"}, // one sign only
+		{"-−5", "en-US"},
+		{"−-5", "en-US"},
+		{"-$-5", "en-US"},
+	}
+	for _, tt := range tests {
+		if got, err := Parse(tt.s, tt.locale); err == nil {
+			t.Errorf("Parse(%q, %s) = %v, want an error", tt.s, tt.locale, got)
+		}
+	}
+
+	var unknown *UnknownLocaleError
+	if _, err := Parse("1", "xx-XX"); !errors.As(err, &unknown) {
+		t.Errorf("unknown locale: got %v, want an *UnknownLocaleError", err)
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	amounts := []int64{0, 1, -1, 99, 100, 123456, -98765432, 1 << 40}
+	for tag := range locales {
+		l := locales[tag]
+		for _, a := range amounts {
+			m := Money{a, l.Currency}
+			s, err := Format(m, l.Tag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Parse(s, l.Tag)
+			if err != nil || got != m {
+				t.Errorf("%s: Parse(Format(%v)) = Parse(%q) = %v, %v", l.Tag, m, s, got, err)
+			}
+		}
+	}
+}
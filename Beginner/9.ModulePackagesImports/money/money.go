@@ -0,0 +1,175 @@
+// Package money provides various utilities to make it easy to manage money.
+//
+// Amounts are kept as an integer number of minor units (cents for USD,
+// yen for JPY), so adding and subtracting is always exact. Operations
+// that can produce fractions of a minor unit, such as multiplying or
+// converting, compute the exact result with math/big and then round it
+// with banker's rounding (round half to even).
+package money
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Money represents the combination of an amount of money
+// and the currency the money is in.
+type Money struct {
+	Amount   int64  // in minor units of Currency, e.g. 1050 is $10.50
+	Currency string // ISO 4217 code
+}
+
+// New returns amount minor units of currency.
+func New(amount int64, currency string) (Money, error) {
+	c, err := LookupCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: c.Code}, nil
+}
+
+// FromString parses a decimal amount in major units, such as "10.50"
+// or "-3", into Money. Digits beyond the currency's minor units are
+// rounded half to even.
+func FromString(amount, currency string) (Money, error) {
+	c, err := LookupCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok || strings.ContainsAny(amount, "/eE") {
+		return Money{}, fmt.Errorf("money: invalid amount %q", amount)
+	}
+	minor, ok := roundHalfEven(r.Mul(r, pow10(c.Exponent)))
+	if !ok {
+		return Money{}, &OverflowError{Op: "FromString"}
+	}
+	return Money{Amount: minor, Currency: c.Code}, nil
+}
+
+// exponent returns the number of minor unit digits of m's currency, or
+// an *UnknownCurrencyError if there is no such currency, as in a Money
+// literal with a mistyped code.
+func (m Money) exponent() (int, error) {
+	c, ok := currencies[m.Currency]
+	if !ok {
+		return 0, &UnknownCurrencyError{Code: m.Currency}
+	}
+	return c.Exponent, nil
+}
+
+// Decimal returns the amount in major units, e.g. "10.50".
+func (m Money) Decimal() (string, error) {
+	exp, err := m.exponent()
+	if err != nil {
+		return "", err
+	}
+	neg := m.Amount < 0
+	// Work on the magnitude as uint64 so that math.MinInt64 is handled too.
+	mag := uint64(m.Amount)
+	if neg {
+		mag = -mag
+	}
+	digits := fmt.Sprintf("%0*d", exp+1, mag)
+	s := digits
+	if exp > 0 {
+		s = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s, nil
+}
+
+// String returns the amount and currency code, e.g. "10.50 USD". Since
+// it cannot fail, an unknown currency is shown with the amount in minor
+// units, e.g. "1050 minor units of XYZ".
+func (m Money) String() string {
+	d, err := m.Decimal()
+	if err != nil {
+		return fmt.Sprintf("%d minor units of %s", m.Amount, m.Currency)
+	}
+	return d + " " + m.Currency
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool { return m.Amount == 0 }
+
+// sameCurrency returns a *CurrencyMismatchError if m and o differ in currency.
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency != o.Currency {
+		return &CurrencyMismatchError{A: m.Currency, B: o.Currency}
+	}
+	return nil
+}
+
+// Add returns m + o. Both must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + o.Amount
+	if (o.Amount > 0 && sum < m.Amount) || (o.Amount < 0 && sum > m.Amount) {
+		return Money{}, &OverflowError{Op: "Add"}
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub returns m - o. Both must be in the same currency.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	neg, err := o.Neg()
+	if err != nil {
+		return Money{}, &OverflowError{Op: "Sub"}
+	}
+	return m.Add(neg)
+}
+
+// Neg returns -m. The smallest amount, math.MinInt64 minor units, has no
+// positive counterpart and gives an *OverflowError.
+func (m Money) Neg() (Money, error) {
+	if m.Amount == math.MinInt64 {
+		return Money{}, &OverflowError{Op: "Neg"}
+	}
+	return Money{Amount: -m.Amount, Currency: m.Currency}, nil
+}
+
+// Cmp compares m and o and returns -1, 0 or +1. Both must be in the same
+// currency.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Mul returns m multiplied by factor, which is a decimal string such as
+// "1.075" or "0.2", rounded half to even to a whole minor unit.
+func (m Money) Mul(factor string) (Money, error) {
+	f, ok := new(big.Rat).SetString(factor)
+	if !ok {
+		return Money{}, fmt.Errorf("money: invalid factor %q", factor)
+	}
+	return m.MulRat(f)
+}
+
+// MulRat returns m multiplied by f, rounded half to even to a whole
+// minor unit.
+func (m Money) MulRat(f *big.Rat) (Money, error) {
+	r := new(big.Rat).SetInt64(m.Amount)
+	amount, ok := roundHalfEven(r.Mul(r, f))
+	if !ok {
+		return Money{}, &OverflowError{Op: "Mul"}
+	}
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
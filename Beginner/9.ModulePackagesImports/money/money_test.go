@@ -0,0 +1,207 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		from Money
+		to   string
+		want Money
+	}{
+		{Money{1000, "USD"}, "INR", Money{83200, "INR"}},
+		{Money{92, "EUR"}, "USD", Money{100, "USD"}},
+		// No EUR/INR rate is listed; it goes through USD.
+		{Money{92, "EUR"}, "INR", Money{8320, "INR"}},
+		{Money{5, "EUR"}, "EUR", Money{5, "EUR"}},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.from, tt.to)
+		if err != nil {
+			t.Errorf("Convert(%v, %s): %v", tt.from, tt.to, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Convert(%v, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	var unknown *UnknownCurrencyError
+	if _, err := Convert(Money{1, "USD"}, "XXX"); !errors.As(err, &unknown) {
+		t.Errorf("Convert to XXX: got %v, want an *UnknownCurrencyError", err)
+	}
+}
+
+func TestConverterNilRates(t *testing.T) {
+	var c Converter
+	if _, err := c.Convert(Money{100, "USD"}, "EUR"); err != ErrNoRates {
+		t.Errorf("Convert: got %v, want ErrNoRates", err)
+	}
+	if _, err := c.ConvertOn(time.Time{}, Money{100, "USD"}, "EUR"); err != ErrNoRates {
+		t.Errorf("ConvertOn: got %v, want ErrNoRates", err)
+	}
+	if got, err := c.Convert(Money{100, "USD"}, "USD"); err != nil || got != (Money{100, "USD"}) {
+		t.Errorf("Convert to the same currency = %v, %v", got, err)
+	}
+}
+
+func TestNeg(t *testing.T) {
+	if got, err := (Money{5, "USD"}).Neg(); err != nil || got != (Money{-5, "USD"}) {
+		t.Errorf("Neg = %v, %v", got, err)
+	}
+	var overflow *OverflowError
+	if _, err := (Money{math.MinInt64, "USD"}).Neg(); !errors.As(err, &overflow) {
+		t.Errorf("Neg(MinInt64): got %v, want an *OverflowError", err)
+	}
+	if _, err := (Money{0, "USD"}).Sub(Money{math.MinInt64, "USD"}); !errors.As(err, &overflow) {
+		t.Errorf("Sub(MinInt64): got %v, want an *OverflowError", err)
+	}
+	var mismatch *CurrencyMismatchError
+	if _, err := (Money{0, "USD"}).Sub(Money{math.MinInt64, "EUR"}); !errors.As(err, &mismatch) {
+		t.Errorf("Sub in another currency: got %v, want a *CurrencyMismatchError", err)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{Money{1050, "USD"}, "10.50"},
+		{Money{-5, "USD"}, "-0.05"},
+		{Money{1050, "JPY"}, "1050"},
+		{Money{math.MinInt64, "USD"}, "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		if got, err := tt.m.Decimal(); err != nil || got != tt.want {
+			t.Errorf("%#v.Decimal() = %q, %v; want %q", tt.m, got, err, tt.want)
+		}
+	}
+
+	m := Money{1050, "XYZ"}
+	var unknown *UnknownCurrencyError
+	if _, err := m.Decimal(); !errors.As(err, &unknown) {
+		t.Errorf("Decimal of XYZ: got %v, want an *UnknownCurrencyError", err)
+	}
+	if got := m.String(); got != "1050 minor units of XYZ" {
+		t.Errorf("String of XYZ = %q", got)
+	}
+}
+
+func TestFromString(t *testing.T) {
+	tests := []struct {
+		amount, currency string
+		want             Money
+	}{
+		{"10.50", "USD", Money{1050, "USD"}},
+		{"-3", "USD", Money{-300, "USD"}},
+		{" 7 ", "usd", Money{700, "USD"}},
+		{"0.05", "EUR", Money{5, "EUR"}},
+		{"1.005", "BHD", Money{1005, "BHD"}},
+		{"1050", "JPY", Money{1050, "JPY"}},
+		// Extra digits are rounded half to even.
+		{"0.125", "USD", Money{12, "USD"}},
+		{"0.135", "USD", Money{14, "USD"}},
+		{"-0.125", "USD", Money{-12, "USD"}},
+		{"0.1251", "USD", Money{13, "USD"}},
+		{"12.5", "JPY", Money{12, "JPY"}},
+		{"13.5", "JPY", Money{14, "JPY"}},
+		{"92233720368547758.07", "USD", Money{math.MaxInt64, "USD"}},
+	}
+	for _, tt := range tests {
+		got, err := FromString(tt.amount, tt.currency)
+		if err != nil || got != tt.want {
+			t.Errorf("FromString(%q, %s) = %v, %v; want %v", tt.amount, tt.currency, got, err, tt.want)
+		}
+	}
+}
+
+func TestFromStringErrors(t *testing.T) {
+	var unknown *UnknownCurrencyError
+	if _, err := FromString("1", "XYZ"); !errors.As(err, &unknown) {
+		t.Errorf("FromString in XYZ: got %v, want an *UnknownCurrencyError", err)
+	}
+	var overflow *OverflowError
+	if _, err := FromString("92233720368547758.08", "USD"); !errors.As(err, &overflow) {
+		t.Errorf("FromString too large: got %v, want an *OverflowError", err)
+	}
+	for _, amount := range []string{"", "abc", "1/2", "1e3", "1E3", "1.2.3", "$5"} {
+		if got, err := FromString(amount, "USD"); err == nil {
+			t.Errorf("FromString(%q) = %v, want an error", amount, got)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		a, b Money
+		want Money
+	}{
+		{Money{1050, "USD"}, Money{25, "USD"}, Money{1075, "USD"}},
+		{Money{1050, "USD"}, Money{-1100, "USD"}, Money{-50, "USD"}},
+		{Money{math.MaxInt64, "USD"}, Money{math.MinInt64, "USD"}, Money{-1, "USD"}},
+		{Money{math.MaxInt64 - 1, "USD"}, Money{1, "USD"}, Money{math.MaxInt64, "USD"}},
+	}
+	for _, tt := range tests {
+		if got, err := tt.a.Add(tt.b); err != nil || got != tt.want {
+			t.Errorf("%v.Add(%v) = %v, %v; want %v", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+
+	var overflow *OverflowError
+	for _, tt := range []struct{ a, b int64 }{
+		{math.MaxInt64, 1},
+		{math.MinInt64, -1},
+		{math.MaxInt64 / 2, math.MaxInt64/2 + 2},
+	} {
+		if got, err := (Money{tt.a, "USD"}).Add(Money{tt.b, "USD"}); !errors.As(err, &overflow) {
+			t.Errorf("%d + %d = %v, %v; want an *OverflowError", tt.a, tt.b, got, err)
+		}
+	}
+	var mismatch *CurrencyMismatchError
+	if _, err := (Money{1, "USD"}).Add(Money{1, "EUR"}); !errors.As(err, &mismatch) {
+		t.Errorf("USD + EUR: got %v, want a *CurrencyMismatchError", err)
+	} else if mismatch.A != "USD" || mismatch.B != "EUR" {
+		t.Errorf("mismatch error = %+v", mismatch)
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		m      Money
+		factor string
+		want   int64
+	}{
+		{Money{1000, "USD"}, "1.075", 1075},
+		{Money{1000, "USD"}, "0.2", 200},
+		{Money{1000, "USD"}, "-1", -1000},
+		{Money{1000, "USD"}, "0", 0},
+		// Half a minor unit rounds to the even neighbour.
+		{Money{1, "USD"}, "0.5", 0},
+		{Money{3, "USD"}, "0.5", 2},
+		{Money{-3, "USD"}, "0.5", -2},
+		{Money{125, "USD"}, "0.1", 12},
+		{Money{135, "USD"}, "0.1", 14},
+		{Money{math.MaxInt64, "USD"}, "1", math.MaxInt64},
+	}
+	for _, tt := range tests {
+		got, err := tt.m.Mul(tt.factor)
+		if err != nil || got != (Money{tt.want, tt.m.Currency}) {
+			t.Errorf("%v.Mul(%q) = %v, %v; want %d", tt.m, tt.factor, got, err, tt.want)
+		}
+	}
+
+	if _, err := (Money{1, "USD"}).Mul("lots"); err == nil {
+		t.Error("Mul(\"lots\") succeeded")
+	}
+	var overflow *OverflowError
+	for _, factor := range []string{"2", "-1.5"} {
+		if got, err := (Money{math.MaxInt64, "USD"}).Mul(factor); !errors.As(err, &overflow) {
+			t.Errorf("MaxInt64.Mul(%q) = %v, %v; want an *OverflowError", factor, got, err)
+		}
+	}
+}
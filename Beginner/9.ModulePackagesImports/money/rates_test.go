@@ -0,0 +1,490 @@
+package money
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rateServer stubs an exchange rate API. It answers with body, in which
+// %s is replaced by the requested base currency.
+func rateServer(t *testing.T, body string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		fmt.Fprintf(w, body, r.URL.Query().Get("base"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &queries
+}
+
+func TestHTTPRates(t *testing.T) {
+	srv, queries := rateServer(t, `{"base": %q, "date": "2024-01-02", "rates": {"EUR": 0.9123}}`)
+	h := HTTPRates{URL: srv.URL}
+
+	r, err := h.Rate("USD", "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.RatString() != "9123/10000" {
+		t.Errorf("Rate = %s, want 0.9123 exactly", r.RatString())
+	}
+	if _, err := h.RateOn(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "USD", "EUR"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"base=USD&symbols=EUR", "base=USD&date=2024-01-02&symbols=EUR"}
+	if strings.Join(*queries, " ") != strings.Join(want, " ") {
+		t.Errorf("queries = %q, want %q", *queries, want)
+	}
+
+	var noRate *NoRateError
+	if _, err := h.Rate("USD", "INR"); !errors.As(err, &noRate) {
+		t.Errorf("missing rate: got %v, want a *NoRateError", err)
+	}
+}
+
+func TestHTTPRatesWrongBase(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"base": "EUR", "rates": {"INR": 90}}`)
+	}))
+	defer srv.Close()
+	if _, err := (HTTPRates{URL: srv.URL}).Rate("USD", "INR"); err == nil || !strings.Contains(err.Error(), "base") {
+		t.Errorf("got %v, want an error about the base currency", err)
+	}
+}
+
+func TestHTTPRatesInvalidRate(t *testing.T) {
+	srv, _ := rateServer(t, `{"base": %q, "rates": {"EUR": -1}}`)
+	var invalid *InvalidRateError
+	if _, err := (HTTPRates{URL: srv.URL}).Rate("USD", "EUR"); !errors.As(err, &invalid) {
+		t.Errorf("got %v, want an *InvalidRateError", err)
+	}
+}
+
+func TestHTTPRatesStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	if _, err := (HTTPRates{URL: srv.URL}).Rate("USD", "EUR"); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("got %v, want an error with the status", err)
+	}
+}
+
+func TestHTTPRatesContext(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := (HTTPRates{URL: srv.URL}).RateContext(ctx, "USD", "EUR"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestStaticRatesInvalid(t *testing.T) {
+	s := StaticRates{"USD/EUR": "0,92", "USD/CAD": "1.36"}
+	var invalid *InvalidRateError
+	if _, err := s.Rate("USD", "EUR"); !errors.As(err, &invalid) {
+		t.Errorf("malformed entry: got %v, want an *InvalidRateError", err)
+	}
+	if _, err := s.Rate("EUR", "USD"); !errors.As(err, &invalid) {
+		t.Errorf("malformed inverse entry: got %v, want an *InvalidRateError", err)
+	}
+	var noRate *NoRateError
+	if _, err := s.Rate("USD", "INR"); !errors.As(err, &noRate) {
+		t.Errorf("missing entry: got %v, want a *NoRateError", err)
+	}
+	// A broken rate is reported, not routed around.
+	tri := Triangulated{Source: StaticRates{"EUR/INR": "x", "EUR/USD": "1.1", "USD/INR": "83"}, Base: "USD"}
+	if _, err := tri.Rate("EUR", "INR"); !errors.As(err, &invalid) {
+		t.Errorf("Triangulated: got %v, want an *InvalidRateError", err)
+	}
+}
+
+func TestLoadRatesCSVLineNumbers(t *testing.T) {
+	in := `date,from,to,rate
+# rates from the bank
+# checked by hand
+2024-01-02,USD,EUR,0.9123
+2024-01-02,USD,INR,eighty
+`
+	_, err := LoadRatesCSV(strings.NewReader(in))
+	if err == nil || !strings.Contains(err.Error(), "line 5:") {
+		t.Errorf("got %v, want an error on line 5", err)
+	}
+	var invalid *InvalidRateError
+	if !errors.As(err, &invalid) {
+		t.Errorf("got %v, want an *InvalidRateError", err)
+	}
+}
+
+func TestLoadRatesCSV(t *testing.T) {
+	in := "2024-01-02,USD,EUR,0.9123\n,USD,INR,83.2\n"
+	h, err := LoadRatesCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := h["2024-01-02"]["USD/EUR"]; got != "0.9123" {
+		t.Errorf("USD/EUR on 2024-01-02 = %q", got)
+	}
+	if got := h["0001-01-01"]["USD/INR"]; got != "83.2" {
+		t.Errorf("undated USD/INR = %q", got)
+	}
+}
+
+// fakeSource is a HistoricalRateSource backed by a HistoricalRates table
+// that counts how often it is asked and can be told to fail for a pair.
+type fakeSource struct {
+	rates HistoricalRates
+	errs  map[string]error // by "FROM/TO"
+	calls int
+}
+
+func (f *fakeSource) Rate(from, to string) (*big.Rat, error) {
+	f.calls++
+	if err := f.errs[from+"/"+to]; err != nil {
+		return nil, err
+	}
+	return f.rates.Rate(from, to)
+}
+
+func (f *fakeSource) RateOn(date time.Time, from, to string) (*big.Rat, error) {
+	f.calls++
+	if err := f.errs[from+"/"+to]; err != nil {
+		return nil, err
+	}
+	return f.rates.RateOn(date, from, to)
+}
+
+// fakeClock is a time source for CachedRates that only moves when told.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.t }
+
+func day(s string) time.Time {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCachedRatesTTL(t *testing.T) {
+	src := &fakeSource{rates: HistoricalRates{"2024-01-02": {"USD/EUR": "0.92", "USD/INR": "83.2"}}}
+	clock := &fakeClock{t: day("2024-01-02")}
+	c := NewCachedRates(src, time.Minute)
+	c.Now = clock.Now
+
+	steps := []struct {
+		after     time.Duration // since the start
+		from, to  string
+		wantCalls int
+	}{
+		{0, "USD", "EUR", 1},
+		{30 * time.Second, "USD", "EUR", 1},
+		{59 * time.Second, "USD", "EUR", 1},
+		{59 * time.Second, "USD", "INR", 2}, // another pair has its own entry
+		{time.Minute, "USD", "EUR", 3},      // expired exactly at the TTL
+		{90 * time.Second, "USD", "EUR", 3},
+		{2 * time.Minute, "USD", "EUR", 4},
+		{2 * time.Minute, "USD", "INR", 5},
+	}
+	for _, s := range steps {
+		clock.t = day("2024-01-02").Add(s.after)
+		r, err := c.Rate(s.from, s.to)
+		if err != nil {
+			t.Fatalf("at %v: Rate(%s, %s): %v", s.after, s.from, s.to, err)
+		}
+		want, _ := src.rates.Rate(s.from, s.to)
+		if r.Cmp(want) != 0 {
+			t.Errorf("at %v: Rate(%s, %s) = %s, want %s", s.after, s.from, s.to, r.RatString(), want.RatString())
+		}
+		if src.calls != s.wantCalls {
+			t.Errorf("at %v: source asked %d times, want %d", s.after, src.calls, s.wantCalls)
+		}
+	}
+
+	// The caller gets a copy; changing it does not change the cache.
+	r, _ := c.Rate("USD", "EUR")
+	r.SetInt64(1000)
+	if r, _ := c.Rate("USD", "EUR"); r.RatString() != "23/25" {
+		t.Errorf("cached rate changed to %s", r.RatString())
+	}
+}
+
+func TestCachedRatesErrorsNotCached(t *testing.T) {
+	errDown := errors.New("rate service down")
+	src := &fakeSource{
+		rates: HistoricalRates{"2024-01-02": {"USD/EUR": "0.92"}},
+		errs:  map[string]error{"USD/EUR": errDown},
+	}
+	c := NewCachedRates(src, time.Hour)
+	c.Now = (&fakeClock{t: day("2024-01-02")}).Now
+
+	tests := []struct {
+		from, to  string
+		fix       bool // stop the source failing first
+		wantErr   error
+		wantCalls int
+	}{
+		{"USD", "EUR", false, errDown, 1},
+		{"USD", "EUR", false, errDown, 2},
+		{"USD", "INR", false, &NoRateError{}, 3},
+		{"USD", "INR", false, &NoRateError{}, 4},
+		{"USD", "EUR", true, nil, 5},
+		{"USD", "EUR", false, nil, 5},
+	}
+	for i, tt := range tests {
+		if tt.fix {
+			delete(src.errs, "USD/EUR")
+		}
+		_, err := c.Rate(tt.from, tt.to)
+		switch want := tt.wantErr.(type) {
+		case nil:
+			if err != nil {
+				t.Errorf("%d: Rate(%s, %s): %v", i, tt.from, tt.to, err)
+			}
+		case *NoRateError:
+			if !errors.As(err, &want) {
+				t.Errorf("%d: Rate(%s, %s) error = %v, want a *NoRateError", i, tt.from, tt.to, err)
+			}
+		default:
+			if err != want {
+				t.Errorf("%d: Rate(%s, %s) error = %v, want %v", i, tt.from, tt.to, err, want)
+			}
+		}
+		if src.calls != tt.wantCalls {
+			t.Errorf("%d: source asked %d times, want %d", i, src.calls, tt.wantCalls)
+		}
+	}
+}
+
+func TestCachedRatesRateOn(t *testing.T) {
+	src := &fakeSource{rates: HistoricalRates{
+		"2024-01-02": {"USD/EUR": "0.91"},
+		"2024-01-03": {"USD/EUR": "0.93"},
+	}}
+	c := NewCachedRates(src, time.Hour)
+	c.Now = (&fakeClock{t: day("2024-01-04")}).Now
+
+	tests := []struct {
+		date      string
+		want      string
+		wantCalls int
+	}{
+		{"2024-01-02", "91/100", 1},
+		{"2024-01-03", "93/100", 2}, // each day is cached on its own
+		{"2024-01-02", "91/100", 2},
+		{"2024-01-03", "93/100", 2},
+	}
+	for _, tt := range tests {
+		r, err := c.RateOn(day(tt.date), "USD", "EUR")
+		if err != nil || r.RatString() != tt.want {
+			t.Errorf("RateOn(%s) = %v, %v; want %s", tt.date, r, err, tt.want)
+		}
+		if src.calls != tt.wantCalls {
+			t.Errorf("RateOn(%s): source asked %d times, want %d", tt.date, src.calls, tt.wantCalls)
+		}
+	}
+
+	var noRate *NoRateError
+	plain := NewCachedRates(StaticRates{"USD/EUR": "0.92"}, time.Hour)
+	if _, err := plain.RateOn(day("2024-01-02"), "USD", "EUR"); !errors.As(err, &noRate) {
+		t.Errorf("RateOn without a historical source: got %v, want a *NoRateError", err)
+	}
+}
+
+func TestHistoricalRatesRateOn(t *testing.T) {
+	h := HistoricalRates{
+		"2024-01-02": {"USD/EUR": "0.91", "USD/INR": "83"},
+		"2024-01-05": {"USD/EUR": "0.93"},
+		"2024-01-08": {"USD/EUR": "0.94", "USD/INR": "83.5"},
+	}
+	tests := []struct {
+		date     string
+		from, to string
+		want     string // "" for a *NoRateError
+	}{
+		{"2024-01-02", "USD", "EUR", "91/100"},
+		{"2024-01-03", "USD", "EUR", "91/100"}, // no rates that day
+		{"2024-01-05", "USD", "EUR", "93/100"},
+		{"2024-01-06", "USD", "EUR", "93/100"}, // the weekend takes Friday's
+		{"2024-01-05", "USD", "INR", "83"},     // missing that day, so from the 2nd
+		{"2024-01-07", "USD", "INR", "83"},
+		{"2024-01-08", "USD", "INR", "167/2"},
+		{"2024-02-01", "USD", "EUR", "47/50"},
+		{"2024-01-03", "EUR", "USD", "100/91"}, // the inverse of the listed pair
+		{"2024-01-01", "USD", "EUR", ""},       // before the first day
+		{"2024-01-05", "USD", "CAD", ""},
+	}
+	for _, tt := range tests {
+		r, err := h.RateOn(day(tt.date), tt.from, tt.to)
+		if tt.want == "" {
+			var noRate *NoRateError
+			if !errors.As(err, &noRate) {
+				t.Errorf("RateOn(%s, %s, %s) = %v, %v; want a *NoRateError", tt.date, tt.from, tt.to, r, err)
+			}
+			continue
+		}
+		if err != nil || r.RatString() != tt.want {
+			t.Errorf("RateOn(%s, %s, %s) = %v, %v; want %s", tt.date, tt.from, tt.to, r, err, tt.want)
+		}
+	}
+
+	// The time of day and time zone of date do not matter, only its day.
+	late := time.Date(2024, 1, 5, 23, 59, 0, 0, time.FixedZone("UTC-10", -10*3600))
+	if r, err := h.RateOn(late, "USD", "EUR"); err != nil || r.RatString() != "93/100" {
+		t.Errorf("RateOn late on the 5th = %v, %v", r, err)
+	}
+	if r, err := h.Rate("USD", "INR"); err != nil || r.RatString() != "167/2" {
+		t.Errorf("Rate = %v, %v; want the latest, 83.5", r, err)
+	}
+}
+
+func TestLoadRatesJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want HistoricalRates
+	}{
+		{
+			"one day",
+			`{"base": "USD", "date": "2024-01-02", "rates": {"EUR": 0.9123, "INR": 83.20}}`,
+			HistoricalRates{"2024-01-02": {"USD/EUR": "0.9123", "USD/INR": "83.20"}},
+		},
+		{
+			"several days",
+			`[{"base": "USD", "date": "2024-01-02", "rates": {"EUR": 0.91}},
+			  {"base": "EUR", "date": "2024-01-03", "rates": {"GBP": 0.86}}]`,
+			HistoricalRates{"2024-01-02": {"USD/EUR": "0.91"}, "2024-01-03": {"EUR/GBP": "0.86"}},
+		},
+		{
+			"no date",
+			`{"base": "usd", "rates": {"jpy": 148}}`,
+			HistoricalRates{"0001-01-01": {"USD/JPY": "148"}},
+		},
+		{"no rates", `{"base": "USD", "date": "2024-01-02", "rates": {}}`, HistoricalRates{}},
+		{"empty array", ` [] `, HistoricalRates{}},
+	}
+	for _, tt := range tests {
+		got, err := LoadRatesJSON(strings.NewReader(tt.in))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadRatesJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want any // an error type to match with errors.As, or nil for any error
+	}{
+		{"empty", ``, nil},
+		{"not JSON", `rates`, nil},
+		{"truncated", `{"base": "USD", "rates": {"EUR": 0.9`, nil},
+		{"wrong shape", `{"base": "USD", "rates": ["EUR"]}`, new(*json.UnmarshalTypeError)},
+		{"bad date", `{"base": "USD", "date": "02/01/2024", "rates": {"EUR": 0.9}}`, new(*time.ParseError)},
+		{"unknown base", `{"base": "XYZ", "rates": {"EUR": 0.9}}`, new(*UnknownCurrencyError)},
+		{"unknown target", `{"base": "USD", "rates": {"XYZ": 0.9}}`, new(*UnknownCurrencyError)},
+		{"negative rate", `{"base": "USD", "rates": {"EUR": -0.9}}`, new(*InvalidRateError)},
+		{"zero rate", `[{"base": "USD", "rates": {"EUR": 0}}]`, new(*InvalidRateError)},
+	}
+	for _, tt := range tests {
+		_, err := LoadRatesJSON(strings.NewReader(tt.in))
+		if err == nil {
+			t.Errorf("%s: no error", tt.name)
+			continue
+		}
+		if tt.want != nil && !errors.As(err, tt.want) {
+			t.Errorf("%s: got %v (%T), want a %T", tt.name, err, err, tt.want)
+		}
+	}
+}
+
+func TestTriangulatedRateOn(t *testing.T) {
+	hist := HistoricalRates{
+		"2024-01-02": {"EUR/USD": "1.1", "USD/INR": "83"},
+		"2024-01-03": {"EUR/USD": "1.2", "USD/INR": "84", "EUR/GBP": "0.86"},
+	}
+	errDown := errors.New("rate service down")
+	tests := []struct {
+		name     string
+		errs     map[string]error
+		date     string
+		from, to string
+		want     string // the rate, or "" for an error
+		wantErr  error  // nil for a *NoRateError for from/to
+	}{
+		{name: "through USD", date: "2024-01-02", from: "EUR", to: "INR", want: "913/10"},
+		{name: "rates of the day", date: "2024-01-03", from: "EUR", to: "INR", want: "504/5"},
+		{name: "earlier day", date: "2024-01-10", from: "EUR", to: "INR", want: "504/5"},
+		{name: "inverse legs", date: "2024-01-02", from: "INR", to: "EUR", want: "10/913"},
+		{name: "direct", date: "2024-01-03", from: "EUR", to: "GBP", want: "43/50"},
+		{name: "from the base", date: "2024-01-02", from: "USD", to: "INR", want: "83"},
+		{name: "missing leg", date: "2024-01-02", from: "EUR", to: "CAD"},
+		{name: "before the first day", date: "2024-01-01", from: "EUR", to: "INR"},
+		{name: "base with no rate", date: "2024-01-02", from: "USD", to: "CAD"},
+		{
+			name: "first leg fails", date: "2024-01-02", from: "EUR", to: "INR",
+			errs: map[string]error{"EUR/USD": errDown}, wantErr: errDown,
+		},
+		{
+			name: "second leg fails", date: "2024-01-02", from: "EUR", to: "INR",
+			errs: map[string]error{"USD/INR": errDown}, wantErr: errDown,
+		},
+		{
+			name: "direct rate fails", date: "2024-01-03", from: "EUR", to: "GBP",
+			errs: map[string]error{"EUR/GBP": errDown}, wantErr: errDown,
+		},
+	}
+	for _, tt := range tests {
+		tri := Triangulated{Source: &fakeSource{rates: hist, errs: tt.errs}, Base: "USD"}
+		r, err := tri.RateOn(day(tt.date), tt.from, tt.to)
+		switch {
+		case tt.want != "":
+			if err != nil || r.RatString() != tt.want {
+				t.Errorf("%s: RateOn = %v, %v; want %s", tt.name, r, err, tt.want)
+			}
+		case tt.wantErr != nil:
+			if err != tt.wantErr {
+				t.Errorf("%s: RateOn error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		default:
+			var noRate *NoRateError
+			if !errors.As(err, &noRate) || noRate.From != tt.from || noRate.To != tt.to {
+				t.Errorf("%s: RateOn = %v, %v; want a *NoRateError for %s/%s", tt.name, r, err, tt.from, tt.to)
+			}
+		}
+	}
+
+	// An invalid rate on one leg is reported, not turned into a missing one.
+	tri := Triangulated{Source: HistoricalRates{"2024-01-02": {"EUR/USD": "x", "USD/INR": "83"}}, Base: "USD"}
+	var invalid *InvalidRateError
+	if _, err := tri.RateOn(day("2024-01-02"), "EUR", "INR"); !errors.As(err, &invalid) {
+		t.Errorf("invalid leg: got %v, want an *InvalidRateError", err)
+	}
+	var noRate *NoRateError
+	plain := Triangulated{Source: StaticRates{"EUR/USD": "1.1"}, Base: "USD"}
+	if _, err := plain.RateOn(day("2024-01-02"), "EUR", "USD"); !errors.As(err, &noRate) {
+		t.Errorf("RateOn without a historical source: got %v, want a *NoRateError", err)
+	}
+}
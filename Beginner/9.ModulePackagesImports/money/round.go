@@ -0,0 +1,46 @@
+package money
+
+import "math/big"
+
+// pow10 returns 10^n as a big.Rat; n may be negative.
+func pow10(n int) *big.Rat {
+	p := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(n))), nil)
+	if n < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), p)
+	}
+	return new(big.Rat).SetInt(p)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// roundHalfEven rounds r to the nearest integer, choosing the even one
+// when r is exactly halfway between two integers (banker's rounding).
+// Unlike always rounding halves up, this does not bias sums of many
+// rounded values upwards. ok is false if the result does not fit in an
+// int64.
+func roundHalfEven(r *big.Rat) (n int64, ok bool) {
+	num, den := r.Num(), r.Denom()
+	q, m := new(big.Int).QuoRem(num, den, new(big.Int)) // truncates towards zero
+
+	// Compare twice the remainder with the denominator to see which
+	// integer is closer.
+	twice := new(big.Int).Abs(m)
+	twice.Lsh(twice, 1)
+	switch c := twice.Cmp(den); {
+	case c > 0, c == 0 && q.Bit(0) == 1:
+		if num.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	if !q.IsInt64() {
+		return 0, false
+	}
+	return q.Int64(), true
+}
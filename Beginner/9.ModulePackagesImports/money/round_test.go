@@ -0,0 +1,52 @@
+package money
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRoundHalfEven(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"0", 0},
+		{"2.4", 2},
+		{"2.5", 2},
+		{"2.6", 3},
+		{"3.5", 4},
+		{"-2.5", -2},
+		{"-3.5", -4},
+		{"-2.6", -3},
+		{"-0.5", 0},
+		{"12.5", 12},
+		{"13.5", 14},
+		{"1/3", 0},
+		{"5/3", 2},
+		{"9223372036854775807", 9223372036854775807},
+		{"9223372036854775806.5", 9223372036854775806},
+		{"-9223372036854775808", -9223372036854775808},
+		{"-9223372036854775807.5", -9223372036854775808},
+	}
+	for _, tt := range tests {
+		r, _ := new(big.Rat).SetString(tt.in)
+		if got, ok := roundHalfEven(r); !ok || got != tt.want {
+			t.Errorf("roundHalfEven(%s) = %d, %v; want %d", tt.in, got, ok, tt.want)
+		}
+	}
+
+	for _, in := range []string{"9223372036854775807.5", "9223372036854775808", "-9223372036854775808.6", "1e30"} {
+		r, _ := new(big.Rat).SetString(in)
+		if got, ok := roundHalfEven(r); ok {
+			t.Errorf("roundHalfEven(%s) = %d, want it not to fit", in, got)
+		}
+	}
+}
+
+func TestPow10(t *testing.T) {
+	for n, want := range map[int]string{0: "1", 2: "100", 3: "1000", -2: "1/100"} {
+		if got := pow10(n).RatString(); got != want {
+			t.Errorf("pow10(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
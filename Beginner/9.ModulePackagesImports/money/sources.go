@@ -0,0 +1,132 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Triangulated is a RateSource that falls back to going through a base
+// currency when Source has no direct rate: if there is no EUR/INR rate,
+// EUR/USD and USD/INR are multiplied instead.
+type Triangulated struct {
+	Source RateSource
+	Base   string // e.g. "USD"
+}
+
+// Rate implements RateSource.
+func (t Triangulated) Rate(from, to string) (*big.Rat, error) {
+	return t.triangulate(from, to, t.Source.Rate)
+}
+
+// RateOn implements HistoricalRateSource. It fails with a *NoRateError
+// if Source does not implement HistoricalRateSource.
+func (t Triangulated) RateOn(date time.Time, from, to string) (*big.Rat, error) {
+	hist, ok := t.Source.(HistoricalRateSource)
+	if !ok {
+		return nil, &NoRateError{From: from, To: to}
+	}
+	return t.triangulate(from, to, func(f, to string) (*big.Rat, error) {
+		return hist.RateOn(date, f, to)
+	})
+}
+
+// triangulate asks rate for from/to, and if there is none for from/Base
+// and Base/to.
+func (t Triangulated) triangulate(from, to string, rate func(from, to string) (*big.Rat, error)) (*big.Rat, error) {
+	r, err := rate(from, to)
+	var noRate *NoRateError
+	if err == nil || !errors.As(err, &noRate) || from == t.Base || to == t.Base {
+		return r, err
+	}
+	toBase, err := rate(from, t.Base)
+	if err != nil {
+		return nil, t.noRate(from, to, err)
+	}
+	fromBase, err := rate(t.Base, to)
+	if err != nil {
+		return nil, t.noRate(from, to, err)
+	}
+	return new(big.Rat).Mul(toBase, fromBase), nil
+}
+
+// noRate turns a missing rate for one leg of the route through Base into
+// a *NoRateError for the whole pair. Other errors, such as an
+// *InvalidRateError or a failed request, are returned as they are.
+func (t Triangulated) noRate(from, to string, err error) error {
+	var noRate *NoRateError
+	if errors.As(err, &noRate) {
+		return &NoRateError{From: from, To: to}
+	}
+	return err
+}
+
+// CachedRates remembers the rates returned by Source for TTL, so that a
+// slow source, such as an HTTP API, is asked at most once per pair in
+// that time. Errors are not cached. It is safe for concurrent use.
+type CachedRates struct {
+	Source RateSource
+	TTL    time.Duration
+	Now    func() time.Time // defaults to time.Now; tests can replace it
+
+	mu      sync.Mutex
+	entries map[string]cachedRate
+}
+
+type cachedRate struct {
+	rate    *big.Rat
+	expires time.Time
+}
+
+// NewCachedRates returns a CachedRates in front of src.
+func NewCachedRates(src RateSource, ttl time.Duration) *CachedRates {
+	return &CachedRates{Source: src, TTL: ttl}
+}
+
+// Rate implements RateSource.
+func (c *CachedRates) Rate(from, to string) (*big.Rat, error) {
+	return c.cached(from+"/"+to, func() (*big.Rat, error) {
+		return c.Source.Rate(from, to)
+	})
+}
+
+// RateOn implements HistoricalRateSource. It fails with a *NoRateError
+// if Source does not implement HistoricalRateSource.
+func (c *CachedRates) RateOn(date time.Time, from, to string) (*big.Rat, error) {
+	hist, ok := c.Source.(HistoricalRateSource)
+	if !ok {
+		return nil, &NoRateError{From: from, To: to}
+	}
+	return c.cached(date.Format(dateLayout)+" "+from+"/"+to, func() (*big.Rat, error) {
+		return hist.RateOn(date, from, to)
+	})
+}
+
+// cached returns the rate stored under key, calling fetch if there is
+// none or it has expired.
+func (c *CachedRates) cached(key string, fetch func() (*big.Rat, error)) (*big.Rat, error) {
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now().Before(e.expires) {
+		return new(big.Rat).Set(e.rate), nil
+	}
+
+	r, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = map[string]cachedRate{}
+	}
+	c.entries[key] = cachedRate{rate: new(big.Rat).Set(r), expires: now().Add(c.TTL)}
+	c.mu.Unlock()
+	return r, nil
+}